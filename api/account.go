@@ -3,14 +3,47 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
 	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/lib/pq"
 )
 
+type accountResponse struct {
+	ID             int64            `json:"id"`
+	Owner          string           `json:"owner"`
+	Balance        money.Amount     `json:"balance"`
+	Currency       string           `json:"currency"`
+	OverdraftLimit money.Amount     `json:"overdraft_limit"`
+	Status         db.AccountStatus `json:"status"`
+	FrozenBy       string           `json:"frozen_by,omitempty"`
+	FrozenAt       *time.Time       `json:"frozen_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	rsp := accountResponse{
+		ID:             account.ID,
+		Owner:          account.Owner,
+		Balance:        money.New(account.Balance, account.Currency),
+		Currency:       account.Currency,
+		OverdraftLimit: money.New(account.OverdraftLimit, account.Currency),
+		Status:         account.Status,
+		FrozenBy:       account.FrozenBy.String,
+		CreatedAt:      account.CreatedAt,
+	}
+	if account.FrozenAt.Valid {
+		rsp.FrozenAt = &account.FrozenAt.Time
+	}
+	return rsp
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
 }
@@ -43,133 +76,211 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
-type getAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+func (server *Server) getAccount(ctx *gin.Context) {
+	account := ctx.MustGet(accountKey).(db.Account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
-func (server *Server) getAccount(ctx *gin.Context) {
-	var req getAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
+type listAccountRequest struct {
+	Owner string `form:"owner" binding:"omitempty,alphanum"`
+	pageRequest
+}
+
+// accountCursor is the position of the last account of a page, accounts are listed by ID
+type accountCursor struct {
+	ID int64 `json:"id"`
+}
+
+func (server *Server) listAccounts(ctx *gin.Context) {
+	var req listAccountRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
-	if err != nil {
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	owner := authPayload.Username
+	if len(req.Owner) > 0 && req.Owner != owner {
+		if !hasRole(authPayload, staffRoles...) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotOwned))
+			return
+		}
+		owner = req.Owner
+	}
 
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+	scope := "accounts:" + owner
+	var after accountCursor
+	if len(req.After) > 0 {
+		if err := server.cursors.Decode(scope, req.After, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
+	}
 
+	arg := db.ListAccountsAfterParams{
+		Owner:   owner,
+		AfterID: after.ID,
+		Limit:   req.pageLimit() + 1,
+	}
+	accounts, err := server.store.ListAccountsAfter(ctx, arg)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
-	if authPayload.Username != account.Owner {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+	accounts, more := cursor.SplitPage(accounts, req.pageLimit())
+	rsp := pageResponse[accountResponse]{
+		Items: make([]accountResponse, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		rsp.Items = append(rsp.Items, newAccountResponse(account))
+	}
+
+	if more {
+		last := accounts[len(accounts)-1]
+		rsp.NextCursor, err = server.cursors.Encode(scope, accountCursor{ID: last.ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, rsp)
 }
 
-type listAccountRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+// freezeAccount can be called by the owner, e.g. after losing a card, or by staff.
+// The account records who froze it.
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	frozenBy := sql.NullString{String: authPayload.Username, Valid: true}
+	server.changeAccountStatus(ctx, db.AccountStatusActive, db.AccountStatusFrozen, frozenBy)
 }
 
-func (server *Server) listAccounts(ctx *gin.Context) {
-	var req listAccountRequest
-	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+// unfreezeAccount is staff only, so that whoever took over a frozen account cannot unfreeze it
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, db.AccountStatusFrozen, db.AccountStatusActive, sql.NullString{})
+}
+
+func (server *Server) changeAccountStatus(ctx *gin.Context, from db.AccountStatus, to db.AccountStatus, frozenBy sql.NullString) {
+	account := ctx.MustGet(accountKey).(db.Account)
+	if account.Status != from {
+		err := fmt.Errorf("account [%d] is %s, expected %s", account.ID, account.Status, from)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
 		return
 	}
 
-	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
-
-	arg := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	arg := db.UpdateAccountStatusParams{
+		ID:         account.ID,
+		FromStatus: from,
+		Status:     to,
+		FrozenBy:   frozenBy,
 	}
-	accounts, err := server.store.ListAccounts(ctx, arg)
+	account, err := server.store.UpdateAccountStatus(ctx, arg)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("account [%d] is no longer %s", arg.ID, from)
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
-}
-
-type updateAccountURIRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
-}
-
-type updateAccountDataRequest struct {
-	Balance int64 `json:"balance" binding:"required,min=1"`
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
-func (server *Server) updateAccount(ctx *gin.Context) {
-	var uriReq updateAccountURIRequest
-	var dataReq updateAccountDataRequest
-
-	if err := ctx.ShouldBindJSON(&dataReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+// closeAccount replaces deleting accounts: the history that references them is kept
+// and closed accounts can never be debited or credited again. Like unfreezing, closing a
+// frozen account is left to staff, or whoever took over the account could close it.
+func (server *Server) closeAccount(ctx *gin.Context) {
+	account := ctx.MustGet(accountKey).(db.Account)
+	if account.Status == db.AccountStatusClosed {
+		err := fmt.Errorf("account [%d] is already closed", account.ID)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
 		return
 	}
-
-	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	closeFrozen := hasRole(authPayload, staffRoles...)
+	if account.Status == db.AccountStatusFrozen && !closeFrozen {
+		err := fmt.Errorf("account [%d] is frozen, only staff can close it", account.ID)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
-
-	arg := db.UpdateAccountParams{
-		ID:      uriReq.ID,
-		Balance: dataReq.Balance,
+	if account.Balance != 0 {
+		err := fmt.Errorf("account [%d] balance must be zero to close it", account.ID)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
 	}
-	account, err := server.store.UpdateAccount(ctx, arg)
+
+	account, err := server.store.CloseAccount(ctx, db.CloseAccountParams{
+		ID:          account.ID,
+		CloseFrozen: closeFrozen,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			err := errors.New("account changed while closing it, please retry")
+			ctx.JSON(http.StatusConflict, errorResponse(err))
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
-type deleteAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+type updateOverdraftLimitRequest struct {
+	// OverdraftLimit is a decimal in the account currency, zero turns the overdraft off
+	OverdraftLimit string `json:"overdraft_limit" binding:"required"`
 }
 
-func (server *Server) deleteAccount(ctx *gin.Context) {
-	var req deleteAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
+// updateOverdraftLimit lets admins set how far below zero the balance of an account may go.
+// Lowering the limit under a negative balance is allowed, the account just cannot be debited
+// until the balance is back within the limit.
+func (server *Server) updateOverdraftLimit(ctx *gin.Context) {
+	var uriReq accountURIRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateOverdraftLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, req.ID)
+	account, valid := server.loadAccount(ctx, uriReq.ID)
+	if !valid {
+		return
+	}
+
+	limit, err := money.Parse(req.OverdraftLimit, account.Currency)
 	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if limit.IsNegative() {
+		err := errors.New("overdraft limit must not be negative")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
+	account, err = server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: limit.Units(),
+	})
+	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
-
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"deleted_id": req.ID,
-	})
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
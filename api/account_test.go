@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
 	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
 	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,7 @@ import (
 
 func TestGetAccountAPI(t *testing.T) {
 	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
 	account := randomAccount(user.Username)
 
 	testCases := []struct {
@@ -80,6 +83,53 @@ func TestGetAccountAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name:      "Banker",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addRoleAuthorization(t, request, tokenMaker, authoriztionTypeBearer, otherUser.Username, db.UserRoleBanker, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:      "Forbidden",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, otherUser.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name:      "BadRequest",
 			accountID: 0,
@@ -206,10 +256,10 @@ func TestCreateAccountAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "Currency is not USE VND EUR",
+			name: "Currency is not ISO 4217",
 			body: gin.H{
 				"owner":    account.Owner,
-				"currency": "CAD",
+				"currency": "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
@@ -255,14 +305,463 @@ func TestCreateAccountAPI(t *testing.T) {
 	}
 }
 
-// TODO: Need to update
-func TestUpdateAccountAPI(t *testing.T) {
+func TestAccountStatusAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	account := randomAccount(user.Username)
+	frozenAccount := account
+	frozenAccount.Status = db.AccountStatusFrozen
+	frozenAccount.FrozenBy = sql.NullString{String: user.Username, Valid: true}
+	frozenAccount.FrozenAt = sql.NullTime{Time: time.Now().Truncate(time.Second), Valid: true}
+	frozenByBanker := frozenAccount
+	frozenByBanker.FrozenBy = sql.NullString{String: otherUser.Username, Valid: true}
+	emptyAccount := account
+	emptyAccount.Balance = 0
+	closedAccount := emptyAccount
+	closedAccount.Status = db.AccountStatusClosed
+	emptyFrozenAccount := emptyAccount
+	emptyFrozenAccount.Status = db.AccountStatusFrozen
+
+	testCases := []struct {
+		name          string
+		action        string
+		username      string
+		role          db.UserRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "FreezeOK",
+			action:   "freeze",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.UpdateAccountStatusParams{
+					ID:         account.ID,
+					FromStatus: db.AccountStatusActive,
+					Status:     db.AccountStatusFrozen,
+					FrozenBy:   sql.NullString{String: user.Username, Valid: true},
+				}
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(frozenAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, frozenAccount)
+			},
+		},
+		{
+			name:     "FreezeForbidden",
+			action:   "freeze",
+			username: otherUser.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "FreezeBankerOK",
+			action:   "freeze",
+			username: otherUser.Username,
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.UpdateAccountStatusParams{
+					ID:         account.ID,
+					FromStatus: db.AccountStatusActive,
+					Status:     db.AccountStatusFrozen,
+					FrozenBy:   sql.NullString{String: otherUser.Username, Valid: true},
+				}
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(frozenByBanker, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, frozenByBanker)
+			},
+		},
+		{
+			name:     "FreezeAlreadyFrozen",
+			action:   "freeze",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(frozenAccount, nil)
+
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "UnfreezeOK",
+			action:   "unfreeze",
+			username: otherUser.Username,
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(frozenAccount, nil)
+
+				arg := db.UpdateAccountStatusParams{
+					ID:         account.ID,
+					FromStatus: db.AccountStatusFrozen,
+					Status:     db.AccountStatusActive,
+				}
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:     "UnfreezeOwnerForbidden",
+			action:   "unfreeze",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "UnfreezeConcurrentChange",
+			action:   "unfreeze",
+			username: otherUser.Username,
+			role:     db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(frozenAccount, nil)
+
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "CloseOK",
+			action:   "close",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(emptyAccount, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Eq(db.CloseAccountParams{ID: account.ID})).
+					Times(1).
+					Return(closedAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, closedAccount)
+			},
+		},
+		{
+			name:     "CloseNonZeroBalance",
+			action:   "close",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "CloseAlreadyClosed",
+			action:   "close",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(closedAccount, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "CloseFrozen",
+			action:   "close",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(emptyFrozenAccount, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "CloseFrozenByStaff",
+			action:   "close",
+			username: user.Username,
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(emptyFrozenAccount, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Eq(db.CloseAccountParams{ID: account.ID, CloseFrozen: true})).
+					Times(1).
+					Return(closedAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, closedAccount)
+			},
+		},
+		{
+			name:     "CloseForbidden",
+			action:   "close",
+			username: otherUser.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(emptyAccount, nil)
+
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/%s", account.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
 
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
 }
 
-// TODO: Need to update
-func TestDeleteAccountAPI(t *testing.T) {
+func TestUpdateOverdraftLimitAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+	updated := account
+	updated.OverdraftLimit = 25050
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"overdraft_limit": "250.50"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
+				arg := db.UpdateAccountOverdraftLimitParams{
+					ID:             account.ID,
+					OverdraftLimit: 25050,
+				}
+				store.EXPECT().
+					UpdateAccountOverdraftLimit(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, updated)
+			},
+		},
+		{
+			name:     "NotAdmin",
+			username: user.Username,
+			role:     db.UserRoleBanker,
+			body:     gin.H{"overdraft_limit": "250.50"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateAccountOverdraftLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Negative",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"overdraft_limit": "-1.00"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().UpdateAccountOverdraftLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyDecimals",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"overdraft_limit": "250.505"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().UpdateAccountOverdraftLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingLimit",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateAccountOverdraftLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"overdraft_limit": "250.50"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().UpdateAccountOverdraftLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/accounts/%d/overdraft-limit", account.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
 }
 
 // TODO: Need to update
@@ -275,8 +774,9 @@ func TestListAccountAPI(t *testing.T) {
 	}
 
 	type Query struct {
-		pageID   int
-		pageSize int
+		owner string
+		limit int
+		after string
 	}
 
 	testcases := []struct {
@@ -289,21 +789,20 @@ func TestListAccountAPI(t *testing.T) {
 		{
 			name: "OK",
 			query: Query{
-				pageID:   1,
-				pageSize: 5,
+				limit: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.ListAccountsAfterParams{
+					Owner:   user.Username,
+					AfterID: 0,
+					Limit:   int32(n + 1),
 				}
 
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).
 					Times(1).Return(accounts, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
@@ -312,23 +811,84 @@ func TestListAccountAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "InternalError",
+			name: "NextCursor",
 			query: Query{
-				pageID:   1,
-				pageSize: 5,
+				limit: n - 1,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.ListAccountsAfterParams{
+					Owner: user.Username,
+					Limit: int32(n),
 				}
 
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+
+				var rsp pageResponse[json.RawMessage]
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Items, n-1)
+				require.NotEmpty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name: "BankerListsOwner",
+			query: Query{
+				owner: user.Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addRoleAuthorization(t, request, tokenMaker, authoriztionTypeBearer, util.RandomOwner(), db.UserRoleBanker, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				arg := db.ListAccountsAfterParams{
+					Owner: user.Username,
+					Limit: cursor.DefaultLimit + 1,
+				}
+
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+				requireBodyMatchAccounts(t, recorder.Body, accounts)
+			},
+		},
+		{
+			name: "DepositorListsOtherOwner",
+			query: Query{
+				owner: util.RandomOwner(),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, recorder.Code, http.StatusForbidden)
+			},
+		},
+		{
+			name: "InternalError",
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
 					Times(1).Return([]db.Account{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
@@ -336,18 +896,16 @@ func TestListAccountAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "InvalidPageID",
+			name: "InvalidCursor",
 			query: Query{
-				pageID:   -1,
-				pageSize: n,
+				after: "eyJpZCI6MX0.c2lnbmF0dXJl",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
-
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Any()).
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
@@ -355,10 +913,9 @@ func TestListAccountAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "InvalidPageSize",
+			name: "InvalidLimit",
 			query: Query{
-				pageID:   1,
-				pageSize: 1000000,
+				limit: 1000000,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
@@ -366,7 +923,7 @@ func TestListAccountAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
 
 				store.EXPECT().
-					ListAccounts(gomock.Any(), gomock.Any()).
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
@@ -400,8 +957,15 @@ func TestListAccountAPI(t *testing.T) {
 
 			// Add query parameters to request url
 			q := request.URL.Query()
-			q.Add("page_id", fmt.Sprintf("%d", tc.query.pageID))
-			q.Add("page_size", fmt.Sprintf("%d", tc.query.pageSize))
+			if len(tc.query.owner) > 0 {
+				q.Add("owner", tc.query.owner)
+			}
+			if tc.query.limit > 0 {
+				q.Add("limit", fmt.Sprintf("%d", tc.query.limit))
+			}
+			if len(tc.query.after) > 0 {
+				q.Add("after", tc.query.after)
+			}
 			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -421,23 +985,29 @@ func randomAccount(owner string) db.Account {
 		Owner:    owner,
 		Currency: util.RandomCurrency(),
 		Balance:  util.RandomMoney(),
+		Status:   db.AccountStatusActive,
 	}
 }
 
 func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
-	var gotAccount db.Account
-	err = json.Unmarshal(data, &gotAccount)
+	expected, err := json.Marshal(newAccountResponse(account))
 	require.NoError(t, err)
-	require.Equal(t, account, gotAccount)
+	require.JSONEq(t, string(expected), string(data))
 }
 
 func requireBodyMatchAccounts(t *testing.T, body *bytes.Buffer, accounts []db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
-	var gotAccounts []db.Account
-	err = json.Unmarshal(data, &gotAccounts)
+	var rsp pageResponse[json.RawMessage]
+	err = json.Unmarshal(data, &rsp)
 	require.NoError(t, err)
-	require.Equal(t, accounts, gotAccounts)
+
+	require.Len(t, rsp.Items, len(accounts))
+	for i, account := range accounts {
+		expectedData, err := json.Marshal(newAccountResponse(account))
+		require.NoError(t, err)
+		require.JSONEq(t, string(expectedData), string(rsp.Items[i]))
+	}
 }
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/token"
+)
+
+type adjustmentResponse struct {
+	ID                int64        `json:"id"`
+	AccountID         int64        `json:"account_id"`
+	SuspenseAccountID int64        `json:"suspense_account_id"`
+	Amount            money.Amount `json:"amount"`
+	Reason            string       `json:"reason"`
+	Operator          string       `json:"operator"`
+	EntryID           int64        `json:"entry_id"`
+	SuspenseEntryID   int64        `json:"suspense_entry_id"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+func newAdjustmentResponse(adjustment db.BalanceAdjustment, currency string) adjustmentResponse {
+	return adjustmentResponse{
+		ID:                adjustment.ID,
+		AccountID:         adjustment.AccountID,
+		SuspenseAccountID: adjustment.SuspenseAccountID,
+		Amount:            money.New(adjustment.Amount, currency),
+		Reason:            adjustment.Reason,
+		Operator:          adjustment.Operator,
+		EntryID:           adjustment.EntryID,
+		SuspenseEntryID:   adjustment.SuspenseEntryID,
+		CreatedAt:         adjustment.CreatedAt,
+	}
+}
+
+type createAdjustmentRequest struct {
+	// Amount is a signed decimal in the account currency, negative amounts are debited
+	Amount string `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type createAdjustmentResponse struct {
+	Adjustment adjustmentResponse `json:"adjustment"`
+	Account    accountResponse    `json:"account"`
+}
+
+func (server *Server) createAdjustment(ctx *gin.Context) {
+	var uriReq accountURIRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.loadAccount(ctx, uriReq.ID)
+	if !valid {
+		return
+	}
+
+	amount, err := money.Parse(req.Amount, account.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if amount.Units() == 0 {
+		err := errors.New("amount must not be zero")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	result, err := server.store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    amount.Units(),
+		Reason:    req.Reason,
+		Operator:  authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createAdjustmentResponse{
+		Adjustment: newAdjustmentResponse(result.Adjustment, account.Currency),
+		Account:    newAccountResponse(result.Account),
+	})
+}
+
+type listAdjustmentsRequest struct {
+	AccountID int64 `form:"account_id" binding:"omitempty,min=1"`
+	pageRequest
+}
+
+// adjustmentCursor is the position of the last adjustment of a page, adjustments are listed by ID
+type adjustmentCursor struct {
+	ID int64 `json:"id"`
+}
+
+// listAdjustments is the audit trail of balance adjustments, newest first
+func (server *Server) listAdjustments(ctx *gin.Context) {
+	var req listAdjustmentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scope := fmt.Sprintf("adjustments:%d", req.AccountID)
+	var after adjustmentCursor
+	if len(req.After) > 0 {
+		if err := server.cursors.Decode(scope, req.After, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.ListBalanceAdjustmentsParams{
+		AccountID: sql.NullInt64{Int64: req.AccountID, Valid: req.AccountID != 0},
+		AfterID:   sql.NullInt64{Int64: after.ID, Valid: after.ID != 0},
+		Limit:     req.pageLimit() + 1,
+	}
+	rows, err := server.store.ListBalanceAdjustments(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, more := cursor.SplitPage(rows, req.pageLimit())
+	rsp := pageResponse[adjustmentResponse]{
+		Items: make([]adjustmentResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		rsp.Items = append(rsp.Items, newAdjustmentResponse(row.BalanceAdjustment, row.Currency))
+	}
+
+	if more {
+		last := rows[len(rows)-1]
+		rsp.NextCursor, err = server.cursors.Encode(scope, adjustmentCursor{ID: last.BalanceAdjustment.ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
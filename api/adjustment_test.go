@@ -0,0 +1,243 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateAdjustmentAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"amount": "-12.50", "reason": "reverse duplicated card fee"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.AdjustBalanceTxParams{
+					AccountID: account.ID,
+					Amount:    -1250,
+					Reason:    "reverse duplicated card fee",
+					Operator:  admin,
+				}
+				adjusted := account
+				adjusted.Balance -= 1250
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{
+						Adjustment: db.BalanceAdjustment{ID: 1, AccountID: account.ID, Amount: -1250, Reason: arg.Reason, Operator: admin},
+						Account:    adjusted,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, "-12.50", rsp["adjustment"]["amount"])
+				require.Equal(t, admin, rsp["adjustment"]["operator"])
+			},
+		},
+		{
+			name:     "NotAdmin",
+			username: user.Username,
+			role:     db.UserRoleBanker,
+			body:     gin.H{"amount": "100", "reason": "gift"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingReason",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"amount": "100"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ZeroAmount",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"amount": "0.00", "reason": "nothing"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"amount": "100", "reason": "correction"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InsufficientFunds",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"amount": "-100000", "reason": "correction"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/accounts/%d/adjustments", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAdjustmentsAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	accountID := util.RandomInt(1, 1000)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rows := make([]db.ListBalanceAdjustmentsRow, 3)
+	for i := range rows {
+		rows[i] = db.ListBalanceAdjustmentsRow{
+			BalanceAdjustment: db.BalanceAdjustment{ID: int64(30 - i), AccountID: accountID, Amount: 500, Operator: admin},
+			Currency:          money.VND,
+		}
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListBalanceAdjustments(gomock.Any(), gomock.Eq(db.ListBalanceAdjustmentsParams{
+			AccountID: sql.NullInt64{Int64: accountID, Valid: true},
+			Limit:     3,
+		})).
+		Times(1).
+		Return(rows, nil)
+	store.EXPECT().
+		ListBalanceAdjustments(gomock.Any(), gomock.Eq(db.ListBalanceAdjustmentsParams{
+			AccountID: sql.NullInt64{Int64: accountID, Valid: true},
+			AfterID:   sql.NullInt64{Int64: 29, Valid: true},
+			Limit:     3,
+		})).
+		Times(1).
+		Return(rows[2:], nil)
+
+	server := NewTestServer(t, store)
+	listAdjustments := func(query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/admin/adjustments?"+query, nil)
+		require.NoError(t, err)
+
+		addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, admin, db.UserRoleAdmin, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := listAdjustments(fmt.Sprintf("account_id=%d&limit=2", accountID))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var page pageResponse[map[string]any]
+	err := json.Unmarshal(recorder.Body.Bytes(), &page)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "500", page.Items[0]["amount"])
+	require.Equal(t, admin, page.Items[0]["operator"])
+	require.NotEmpty(t, page.NextCursor)
+	next := page.NextCursor
+
+	recorder = listAdjustments(fmt.Sprintf("account_id=%d&limit=2&after=%s", accountID, next))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	page = pageResponse[map[string]any]{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &page)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, float64(28), page.Items[0]["id"])
+	require.Empty(t, page.NextCursor)
+
+	// the cursor is bound to the account filter it was issued for
+	recorder = listAdjustments("limit=2&after=" + next)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
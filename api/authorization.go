@@ -0,0 +1,75 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+)
+
+const accountKey = "account"
+
+var errAccountNotOwned = errors.New("account doesn't belong to the authenticated user")
+
+// staffRoles can view the accounts and transfers of every customer, freeze their accounts
+// and are the only ones who can unfreeze or close frozen accounts
+var staffRoles = []db.UserRole{db.UserRoleBanker, db.UserRoleAdmin}
+
+type accountAction int
+
+const (
+	accountView accountAction = iota
+	accountFreeze
+	accountManage
+)
+
+// authorizeAccount is the policy deciding whether the caller may act on an account.
+// Owners can do anything with their accounts, staff can view and freeze the others.
+func authorizeAccount(authPayload *token.Payload, account db.Account, action accountAction) error {
+	if account.Owner == authPayload.Username {
+		return nil
+	}
+	if action <= accountFreeze && hasRole(authPayload, staffRoles...) {
+		return nil
+	}
+	return errAccountNotOwned
+}
+
+type accountURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// accountMiddleware loads the account of the :id route parameter and aborts with 403
+// unless the caller is allowed the action on it. Handlers read the account back from the context.
+func accountMiddleware(store db.Store, action accountAction) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req accountURIRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		account, err := store.GetAccount(ctx, req.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+		if err := authorizeAccount(authPayload, account, action); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Set(accountKey, account)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import "github.com/lamdangtung/golang-sample-bank/cursor"
+
+// pageRequest is embedded by the requests of cursor paginated routes.
+// After is the next_cursor of the previous page, empty for the first page.
+type pageRequest struct {
+	After string `form:"after"`
+	Limit int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// pageLimit returns the requested page size, or the default one
+func (req pageRequest) pageLimit() int32 {
+	if req.Limit == 0 {
+		return cursor.DefaultLimit
+	}
+	return req.Limit
+}
+
+type pageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/fx"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lib/pq"
+)
+
+type exchangeRateRequest struct {
+	BaseCurrency  string    `json:"base_currency" binding:"required,currency"`
+	QuoteCurrency string    `json:"quote_currency" binding:"required,currency,nefield=BaseCurrency"`
+	Rate          string    `json:"rate" binding:"required"`
+	ValidFrom     time.Time `json:"valid_from" binding:"required"`
+}
+
+type uploadExchangeRatesRequest struct {
+	Rates []exchangeRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
+
+func (server *Server) uploadExchangeRates(ctx *gin.Context) {
+	var req uploadExchangeRatesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := make([]db.CreateExchangeRateParams, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		if _, err := fx.ParseRate(rate.Rate); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		arg = append(arg, db.CreateExchangeRateParams{
+			BaseCurrency:  rate.BaseCurrency,
+			QuoteCurrency: rate.QuoteCurrency,
+			Rate:          rate.Rate,
+			ValidFrom:     rate.ValidFrom,
+		})
+	}
+
+	rates, err := server.fxService.UploadRates(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+type getExchangeRateRequest struct {
+	From   string `form:"from" binding:"required,currency"`
+	To     string `form:"to" binding:"required,currency"`
+	Amount string `form:"amount" binding:"required"`
+}
+
+func (server *Server) getExchangeRate(ctx *gin.Context) {
+	var req getExchangeRateRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := money.Parse(req.Amount, req.From)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !amount.IsPositive() {
+		err := errors.New("amount must be positive")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	conversion, err := server.fxService.Convert(ctx, amount, req.To)
+	if err != nil {
+		ctx.JSON(conversionErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, conversion)
+}
+
+func conversionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, fx.ErrRateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, fx.ErrAmountTooSmall), errors.Is(err, fx.ErrAmountTooLarge):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUploadExchangeRatesAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	validFrom := time.Now().Truncate(time.Second).UTC()
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body: gin.H{
+				"rates": []gin.H{
+					{"base_currency": money.USD, "quote_currency": money.VND, "rate": "24350.5", "valid_from": validFrom},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := []db.CreateExchangeRateParams{
+					{BaseCurrency: money.USD, QuoteCurrency: money.VND, Rate: "24350.5", ValidFrom: validFrom},
+				}
+				store.EXPECT().
+					CreateExchangeRatesTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ExchangeRate{{ID: 1}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotAdmin",
+			username: util.RandomOwner(),
+			role:     db.UserRoleDepositor,
+			body: gin.H{
+				"rates": []gin.H{
+					{"base_currency": money.USD, "quote_currency": money.VND, "rate": "24350.5", "valid_from": validFrom},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidRate",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body: gin.H{
+				"rates": []gin.H{
+					{"base_currency": money.USD, "quote_currency": money.VND, "rate": "-1", "valid_from": validFrom},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "SameCurrency",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body: gin.H{
+				"rates": []gin.H{
+					{"base_currency": money.USD, "quote_currency": money.USD, "rate": "1", "valid_from": validFrom},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/exchange-rates", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
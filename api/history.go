@@ -0,0 +1,222 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+const defaultHistorySort = "-created_at"
+
+// historyRequest holds the filters shared by the entries and transfers of an account.
+// Amounts are decimal strings in the account currency and compare the size of the
+// movement, so min_amount=100 matches both a 150 credit and a 150 debit.
+type historyRequest struct {
+	From      time.Time `form:"from"`
+	To        time.Time `form:"to" binding:"omitempty,gtfield=From"`
+	MinAmount string    `form:"min_amount"`
+	MaxAmount string    `form:"max_amount"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=created_at -created_at amount -amount"`
+	pageRequest
+}
+
+type historyFilter struct {
+	FromTime    sql.NullTime
+	ToTime      sql.NullTime
+	MinAmount   sql.NullInt64
+	MaxAmount   sql.NullInt64
+	OrderBy     string
+	Descending  bool
+	AfterID     sql.NullInt64
+	AfterAmount int64
+	Limit       int32
+	// scope binds the cursors to the account, the kind of rows and the sort order
+	scope string
+}
+
+// historyCursor is the position of the last row of a page. Amount is the size compared
+// by the amount filters, it only matters when sorting by amount.
+type historyCursor struct {
+	ID     int64 `json:"id"`
+	Amount int64 `json:"amount,omitempty"`
+}
+
+// filter converts the request into query arguments for an account in the given currency.
+// The cursor is decoded separately since it needs the scope of the list.
+func (req historyRequest) filter(currency string) (historyFilter, error) {
+	minAmount, err := parseAmountFilter("min_amount", req.MinAmount, currency)
+	if err != nil {
+		return historyFilter{}, err
+	}
+	maxAmount, err := parseAmountFilter("max_amount", req.MaxAmount, currency)
+	if err != nil {
+		return historyFilter{}, err
+	}
+	if minAmount.Valid && maxAmount.Valid && minAmount.Int64 > maxAmount.Int64 {
+		return historyFilter{}, errors.New("min_amount must not be greater than max_amount")
+	}
+
+	sort := req.Sort
+	if len(sort) == 0 {
+		sort = defaultHistorySort
+	}
+
+	return historyFilter{
+		FromTime:   sql.NullTime{Time: req.From, Valid: !req.From.IsZero()},
+		ToTime:     sql.NullTime{Time: req.To, Valid: !req.To.IsZero()},
+		MinAmount:  minAmount,
+		MaxAmount:  maxAmount,
+		OrderBy:    strings.TrimPrefix(sort, "-"),
+		Descending: strings.HasPrefix(sort, "-"),
+		Limit:      req.pageLimit(),
+	}, nil
+}
+
+func parseAmountFilter(field string, value string, currency string) (sql.NullInt64, error) {
+	if len(value) == 0 {
+		return sql.NullInt64{}, nil
+	}
+
+	amount, err := money.Parse(value, currency)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	if amount.IsNegative() {
+		return sql.NullInt64{}, fmt.Errorf("%s must not be negative", field)
+	}
+	return sql.NullInt64{Int64: amount.Units(), Valid: true}, nil
+}
+
+// bindHistoryFilter binds the query of an account history route and writes a 400 on failure
+func (server *Server) bindHistoryFilter(ctx *gin.Context, kind string, account db.Account) (historyFilter, bool) {
+	var req historyRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return historyFilter{}, false
+	}
+
+	filter, err := req.filter(account.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return historyFilter{}, false
+	}
+
+	filter.scope = fmt.Sprintf("%s:%d:%s:%t", kind, account.ID, filter.OrderBy, filter.Descending)
+	if len(req.After) > 0 {
+		var after historyCursor
+		if err := server.cursors.Decode(filter.scope, req.After, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return historyFilter{}, false
+		}
+		filter.AfterID = sql.NullInt64{Int64: after.ID, Valid: true}
+		filter.AfterAmount = after.Amount
+	}
+	return filter, true
+}
+
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	account := ctx.MustGet(accountKey).(db.Account)
+	filter, ok := server.bindHistoryFilter(ctx, "entries", account)
+	if !ok {
+		return
+	}
+
+	entries, err := server.store.SearchEntries(ctx, db.SearchEntriesParams{
+		AccountID:   account.ID,
+		FromTime:    filter.FromTime,
+		ToTime:      filter.ToTime,
+		MinAmount:   filter.MinAmount,
+		MaxAmount:   filter.MaxAmount,
+		AfterID:     filter.AfterID,
+		AfterAmount: filter.AfterAmount,
+		OrderBy:     filter.OrderBy,
+		Descending:  filter.Descending,
+		Limit:       filter.Limit + 1,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	entries, more := cursor.SplitPage(entries, filter.Limit)
+	rsp := pageResponse[entryResponse]{
+		Items: make([]entryResponse, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		rsp.Items = append(rsp.Items, newEntryResponse(entry, account.Currency))
+	}
+
+	if more {
+		last := entries[len(entries)-1]
+		rsp.NextCursor, err = server.cursors.Encode(filter.scope, historyCursor{ID: last.ID, Amount: absInt64(last.Amount)})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listAccountTransfers(ctx *gin.Context) {
+	account := ctx.MustGet(accountKey).(db.Account)
+	filter, ok := server.bindHistoryFilter(ctx, "transfers", account)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.SearchTransfers(ctx, db.SearchTransfersParams{
+		AccountID:   account.ID,
+		FromTime:    filter.FromTime,
+		ToTime:      filter.ToTime,
+		MinAmount:   filter.MinAmount,
+		MaxAmount:   filter.MaxAmount,
+		AfterID:     filter.AfterID,
+		AfterAmount: filter.AfterAmount,
+		OrderBy:     filter.OrderBy,
+		Descending:  filter.Descending,
+		Limit:       filter.Limit + 1,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, more := cursor.SplitPage(rows, filter.Limit)
+	rsp := pageResponse[transferResponse]{
+		Items: make([]transferResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		rsp.Items = append(rsp.Items, newTransferResponse(row.Transfer, row.FromCurrency, row.ToCurrency))
+	}
+
+	if more {
+		last := rows[len(rows)-1].Transfer
+		amount := last.ToAmount
+		if last.FromAccountID == account.ID {
+			amount = last.Amount
+		}
+		rsp.NextCursor, err = server.cursors.Encode(filter.scope, historyCursor{ID: last.ID, Amount: amount})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListAccountEntriesAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	entries := []db.Entry{
+		{ID: 2, AccountID: account.ID, Amount: -2500, CreatedAt: from.Add(time.Hour)},
+		{ID: 1, AccountID: account.ID, Amount: 1000, CreatedAt: from},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			query: url.Values{
+				"from":       {from.Format(time.RFC3339)},
+				"to":         {to.Format(time.RFC3339)},
+				"min_amount": {"10"},
+				"max_amount": {"25.50"},
+				"sort":       {"-amount"},
+				"limit":      {"5"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchEntriesParams{
+					AccountID:  account.ID,
+					FromTime:   sql.NullTime{Time: from, Valid: true},
+					ToTime:     sql.NullTime{Time: to, Valid: true},
+					MinAmount:  sql.NullInt64{Int64: 1000, Valid: true},
+					MaxAmount:  sql.NullInt64{Int64: 2550, Valid: true},
+					OrderBy:    "amount",
+					Descending: true,
+					Limit:      6,
+				}
+				store.EXPECT().
+					SearchEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[map[string]any]
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, "-25.00", rsp.Items[0]["amount"])
+				require.Equal(t, "10.00", rsp.Items[1]["amount"])
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:     "Defaults",
+			username: user.Username,
+			query:    url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchEntriesParams{
+					AccountID:  account.ID,
+					OrderBy:    "created_at",
+					Descending: true,
+					Limit:      cursor.DefaultLimit + 1,
+				}
+				store.EXPECT().
+					SearchEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Banker",
+			username: util.RandomOwner(),
+			role:     db.UserRoleBanker,
+			query:    url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Forbidden",
+			username: util.RandomOwner(),
+			query:    url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidSort",
+			username: user.Username,
+			query:    url.Values{"sort": {"owner"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidDateRange",
+			username: user.Username,
+			query: url.Values{
+				"from": {to.Format(time.RFC3339)},
+				"to":   {from.Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidAmountRange",
+			username: user.Username,
+			query:    url.Values{"min_amount": {"20"}, "max_amount": {"10"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyDecimals",
+			username: user.Username,
+			query:    url.Values{"min_amount": {"0.001"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			username: user.Username,
+			query:    url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				Return(account, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/entries?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAccountEntriesCursor(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		AnyTimes().
+		Return(account, nil)
+	server := NewTestServer(t, store)
+
+	listEntries := func(query url.Values) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/accounts/%d/entries?%s", account.ID, query.Encode())
+		request, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// one more row than the limit means another page follows
+	firstPage := []db.Entry{
+		{ID: 9, AccountID: account.ID, Amount: 700},
+		{ID: 8, AccountID: account.ID, Amount: -500},
+		{ID: 7, AccountID: account.ID, Amount: 300},
+	}
+	store.EXPECT().
+		SearchEntries(gomock.Any(), gomock.Eq(db.SearchEntriesParams{
+			AccountID:  account.ID,
+			OrderBy:    "amount",
+			Descending: true,
+			Limit:      3,
+		})).
+		Times(1).
+		Return(firstPage, nil)
+
+	recorder := listEntries(url.Values{"sort": {"-amount"}, "limit": {"2"}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp pageResponse[map[string]any]
+	err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.Len(t, rsp.Items, 2)
+	require.NotEmpty(t, rsp.NextCursor)
+
+	store.EXPECT().
+		SearchEntries(gomock.Any(), gomock.Eq(db.SearchEntriesParams{
+			AccountID:   account.ID,
+			AfterID:     sql.NullInt64{Int64: 8, Valid: true},
+			AfterAmount: 500,
+			OrderBy:     "amount",
+			Descending:  true,
+			Limit:       3,
+		})).
+		Times(1).
+		Return(firstPage[2:], nil)
+
+	recorder = listEntries(url.Values{"sort": {"-amount"}, "limit": {"2"}, "after": {rsp.NextCursor}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var lastPage pageResponse[map[string]any]
+	err = json.Unmarshal(recorder.Body.Bytes(), &lastPage)
+	require.NoError(t, err)
+	require.Len(t, lastPage.Items, 1)
+	require.Empty(t, lastPage.NextCursor)
+
+	// the cursor is bound to the sort order it was issued for
+	recorder = listEntries(url.Values{"sort": {"amount"}, "after": {rsp.NextCursor}})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = listEntries(url.Values{"sort": {"-amount"}, "after": {rsp.NextCursor + "x"}})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestListAccountTransfersAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+
+	rows := []db.SearchTransfersRow{
+		{
+			Transfer:     db.Transfer{ID: 1, FromAccountID: account.ID, ToAccountID: 99, Amount: 1000, ToAmount: 250000, ExchangeRate: "25000"},
+			FromCurrency: money.USD,
+			ToCurrency:   money.VND,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		Times(1).
+		Return(account, nil)
+	arg := db.SearchTransfersParams{
+		AccountID:  account.ID,
+		MinAmount:  sql.NullInt64{Int64: 500, Valid: true},
+		OrderBy:    "created_at",
+		Descending: false,
+		Limit:      6,
+	}
+	store.EXPECT().
+		SearchTransfers(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(rows, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/accounts/%d/transfers?min_amount=5&sort=created_at&limit=5", account.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp pageResponse[map[string]any]
+	err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.Len(t, rsp.Items, 1)
+	require.Equal(t, "10.00", rsp.Items[0]["amount"])
+	require.Equal(t, "250000", rsp.Items[0]["to_amount"])
+}
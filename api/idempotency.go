@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyInFlightStatus = 0
+)
+
+// idempotencyMiddleware lets clients safely retry a POST request by sending an Idempotency-Key header.
+// The first request with a key is executed and its response stored; replays with the same key and body
+// get the stored response back, while a different body with the same key is rejected.
+// It must run after authMiddleware since keys are scoped to the authenticated user.
+func idempotencyMiddleware(store db.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		if len(key) == 0 {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			err := fmt.Errorf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+		// hash the actual path, not the route template, so that a key reused on another :id does not match
+		requestHash := hashRequest(ctx.Request.Method, ctx.Request.URL.RequestURI(), body)
+
+		_, err = store.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
+			Username:    authPayload.Username,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiredAt:   time.Now().Add(ttl),
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				replayIdempotentResponse(ctx, store, authPayload.Username, key, requestHash)
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		writer := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		completed := false
+		defer func() {
+			// let the client retry with the same key after a server error, including a panic that
+			// gin.Recovery turns into a 500, or the key would stay in flight until it expires
+			if completed && writer.Status() < http.StatusInternalServerError {
+				return
+			}
+			err := store.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+				Username: authPayload.Username,
+				Key:      key,
+			})
+			if err != nil {
+				ctx.Error(err)
+			}
+		}()
+		ctx.Next()
+		completed = true
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		// the response is already sent, so a failure here is only recorded on the context
+		_, err = store.UpdateIdempotencyKeyResponse(ctx, db.UpdateIdempotencyKeyResponseParams{
+			Username:       authPayload.Username,
+			Key:            key,
+			ResponseStatus: int32(writer.Status()),
+			ResponseBody:   writer.body.Bytes(),
+		})
+		if err != nil {
+			ctx.Error(err)
+		}
+	}
+}
+
+// PurgeExpiredIdempotencyKeys deletes expired idempotency keys every interval until ctx is done.
+// Expired keys are never replayed, they only took up space until a request reused them.
+func PurgeExpiredIdempotencyKeys(ctx context.Context, store db.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := store.DeleteExpiredIdempotencyKeys(ctx)
+		if err != nil {
+			log.Printf("cannot purge expired idempotency keys: %s", err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("purged %d expired idempotency keys", deleted)
+		}
+	}
+}
+
+func replayIdempotentResponse(ctx *gin.Context, store db.Store, username string, key string, requestHash string) {
+	record, err := store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: username,
+		Key:      key,
+	})
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if record.RequestHash != requestHash {
+		err := errors.New("idempotency key was already used with a different request")
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	if record.ResponseStatus == idempotencyInFlightStatus {
+		err := errors.New("a request with this idempotency key is still being processed")
+		ctx.AbortWithStatusJSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	ctx.Header(idempotentReplayedHeader, "true")
+	ctx.Data(int(record.ResponseStatus), gin.MIMEJSON, record.ResponseBody)
+	ctx.Abort()
+}
+
+func hashRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body so it can be stored
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (writer *responseRecorder) Write(data []byte) (int, error) {
+	writer.body.Write(data)
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *responseRecorder) WriteString(s string) (int, error) {
+	writer.body.WriteString(s)
+	return writer.ResponseWriter.WriteString(s)
+}
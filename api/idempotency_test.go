@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account1 := randomAccount(user.Username)
+	account2 := randomAccount(user.Username)
+	account1.Currency = money.USD
+	account2.Currency = money.USD
+
+	key := util.RandomString(16)
+	body := gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          "0.10",
+		"currency":        money.USD,
+	}
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	requestHash := hashRequest(http.MethodPost, "/transfers", data)
+
+	result := db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10, ToAmount: 10, ExchangeRate: "1"},
+		FromAccount: account1,
+		ToAccount:   account2,
+	}
+	storedBody, err := json.Marshal(newTransferTxResponse(result))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "NoKey",
+			key:  "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FirstRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{Username: user.Username, Key: key, RequestHash: requestHash}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateIdempotencyKeyResponseParams) (db.IdempotencyKey, error) {
+						require.Equal(t, key, arg.Key)
+						require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+						require.JSONEq(t, string(storedBody), string(arg.ResponseBody))
+						return db.IdempotencyKey{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "Replay",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: user.Username, Key: key})).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Username:       user.Username,
+						Key:            key,
+						RequestHash:    requestHash,
+						ResponseStatus: http.StatusOK,
+						ResponseBody:   storedBody,
+					}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.JSONEq(t, string(storedBody), recorder.Body.String())
+			},
+		},
+		{
+			name: "DifferentRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{RequestHash: "other", ResponseStatus: http.StatusOK}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InFlight",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{RequestHash: requestHash}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ServerErrorReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{Username: user.Username, Key: key})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "PanicReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ db.TransferTxParams) (db.TransferTxResult, error) {
+						panic("unexpected")
+					})
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{Username: user.Username, Key: key})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			expectVerifiedUsers(store)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			if len(tc.key) > 0 {
+				request.Header.Set(idempotencyKeyHeader, tc.key)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestIdempotencyKeyReusedOnOtherResource(t *testing.T) {
+	user, _ := createRandomUser(t)
+	key := util.RandomString(16)
+
+	// the key was first used to reverse transfer 1, with the same empty body
+	requestHash := hashRequest(http.MethodPost, "/transfers/1/reverse", nil)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.IdempotencyKey{}, sql.ErrNoRows)
+	store.EXPECT().
+		GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: user.Username, Key: key})).
+		Times(1).
+		Return(db.IdempotencyKey{
+			Username:       user.Username,
+			Key:            key,
+			RequestHash:    requestHash,
+			ResponseStatus: http.StatusOK,
+			ResponseBody:   []byte(`{"transfer":{"id":1}}`),
+		}, nil)
+	store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers/2/reverse", http.NoBody)
+	require.NoError(t, err)
+	request.Header.Set(idempotencyKeyHeader, key)
+
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+}
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	purges := 0
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		DeleteExpiredIdempotencyKeys(gomock.Any()).
+		MinTimes(2).
+		DoAndReturn(func(_ context.Context) (int64, error) {
+			purges++
+			if purges == 2 {
+				cancel()
+			}
+			return 3, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		PurgeExpiredIdempotencyKeys(ctx, store, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge did not stop when its context was done")
+	}
+}
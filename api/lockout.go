@@ -0,0 +1,111 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+// errInvalidCredentials is returned for unknown usernames and wrong passwords alike, so that
+// a login does not tell which usernames exist
+var errInvalidCredentials = errors.New("incorrect username or password")
+
+// reserveLogin counts the login as failed before its password or code is checked, so that
+// parallel guesses cannot all get past the lockout. It responds with 429 and a Retry-After
+// header when the username or the client IP is locked out.
+func (server *Server) reserveLogin(ctx *gin.Context, username string) (*lockout.Reservation, bool) {
+	reservation, err := server.lockout.Reserve(ctx, username, ctx.ClientIP())
+	if err == nil {
+		return reservation, true
+	}
+
+	var lockedErr *lockout.LockedError
+	if errors.As(err, &lockedErr) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+		return nil, false
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	return nil, false
+}
+
+// refundLogin takes back a login reserved by reserveLogin once its password or code was right
+func (server *Server) refundLogin(ctx *gin.Context, reservation *lockout.Reservation) bool {
+	if err := server.lockout.Refund(ctx, reservation); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// requireTransferCode checks the two-factor code of a transfer. Wrong codes count towards the
+// login lockout of the user, or whoever took over a session could guess codes until one is right.
+func (server *Server) requireTransferCode(ctx *gin.Context, username string, amount money.Amount, code string) bool {
+	if code == "" {
+		if err := server.mfa.CheckTransfer(ctx, username, amount, code); err != nil {
+			ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+			return false
+		}
+		return true
+	}
+
+	reservation, ok := server.reserveLogin(ctx, username)
+	if !ok {
+		return false
+	}
+	if err := server.mfa.CheckTransfer(ctx, username, amount, code); err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return false
+	}
+	return server.refundLogin(ctx, reservation)
+}
+
+// requireTOTPCode runs check on a two-factor code of the user. Wrong codes count towards the
+// login lockout like in requireTransferCode, codes refused with a conflict were never checked.
+func (server *Server) requireTOTPCode(ctx *gin.Context, username string, check func() error) bool {
+	reservation, ok := server.reserveLogin(ctx, username)
+	if !ok {
+		return false
+	}
+	if err := check(); err != nil {
+		if mfaErrorStatus(err) == http.StatusConflict && !server.refundLogin(ctx, reservation) {
+			return false
+		}
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return false
+	}
+	return server.refundLogin(ctx, reservation)
+}
+
+// unlockUser lets admins clear the failed logins of a user before the lockout ends.
+// Lockouts of client IPs are left to expire.
+func (server *Server) unlockUser(ctx *gin.Context) {
+	var req userURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.lockout.Reset(ctx, user.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(user))
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func login(t *testing.T, server *Server, username string, password string) *httptest.ResponseRecorder {
+	data, err := json.Marshal(gin.H{"username": username, "password": password})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func expectNoMFA(store *mockdb.MockStore) {
+	store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).AnyTimes().Return(db.UserTotp{}, sql.ErrNoRows)
+}
+
+func TestLoginUserUniformError(t *testing.T) {
+	user, password := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Not(user.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+
+	wrongPassword := login(t, server, user.Username, "incorrect")
+	require.Equal(t, http.StatusUnauthorized, wrongPassword.Code)
+
+	unknownUser := login(t, server, util.RandomOwner(), password)
+	require.Equal(t, http.StatusUnauthorized, unknownUser.Code)
+
+	require.Equal(t, wrongPassword.Body.String(), unknownUser.Body.String())
+	require.Contains(t, unknownUser.Body.String(), errInvalidCredentials.Error())
+}
+
+func TestLoginUserLockout(t *testing.T) {
+	user, password := createRandomUser(t)
+	admin, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(user, nil)
+	expectNoMFA(store)
+	store.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+			return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken, ExpiredAt: arg.ExpiredAt}, nil
+		})
+
+	server := NewTestServer(t, store)
+
+	for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+		recorder := login(t, server, user.Username, "incorrect")
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	// once locked out, even the right password is refused
+	recorder := login(t, server, user.Username, password)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+
+	recorder = httptest.NewRecorder()
+	url := fmt.Sprintf("/admin/users/%s/unlock", user.Username)
+	request, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+	addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, admin.Username, db.UserRoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = login(t, server, user.Username, password)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestLoginUserLockoutParallel(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(user, nil)
+
+	server := NewTestServer(t, store)
+
+	// a burst of guesses is not checked faster than the lockout can count them
+	n := 20
+	codes := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- login(t, server, user.Username, util.RandomPassword()).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	guesses := 0
+	for code := range codes {
+		if code == http.StatusUnauthorized {
+			guesses++
+			continue
+		}
+		require.Equal(t, http.StatusTooManyRequests, code)
+	}
+	require.Equal(t, int(server.config.LoginMaxFailures), guesses)
+}
+
+func TestLoginUserLockoutOfUnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(3).Return(db.User{}, sql.ErrNoRows)
+
+	server := NewTestServer(t, store)
+	username := util.RandomOwner()
+
+	// unknown usernames are locked out like existing ones, so a lockout reveals nothing
+	for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+		recorder := login(t, server, username, util.RandomPassword())
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+	recorder := login(t, server, username, util.RandomPassword())
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
+
+func TestLoginUserLockoutIgnoresForwardedFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().Return(db.User{}, sql.ErrNoRows)
+
+	server := NewTestServer(t, store)
+
+	loginFrom := func(forwardedFor string) *httptest.ResponseRecorder {
+		data, err := json.Marshal(gin.H{"username": util.RandomOwner(), "password": util.RandomPassword()})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+		require.NoError(t, err)
+		request.RemoteAddr = "203.0.113.7:54321"
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// no proxy is trusted, so a new X-Forwarded-For per guess does not give the client a new IP
+	for i := 0; i < int(server.config.LoginMaxFailuresPerIP); i++ {
+		recorder := loginFrom(fmt.Sprintf("198.51.100.%d", i+1))
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	recorder := loginFrom("192.0.2.1")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
+
+func TestUnlockUserAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	admin, _ := createRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin.Username,
+			role:     db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, rsp.Username)
+			},
+		},
+		{
+			name:     "NotAdmin",
+			username: user.Username,
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "UserNotFound",
+			username: admin.Username,
+			role:     db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/unlock", user.Username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -6,18 +6,42 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func NewTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		PublicBaseURL:         "http://localhost:8080",
+		VerifyEmailDuration:   time.Hour,
+		PasswordResetDuration: time.Hour,
+		MFATokenDuration:      time.Minute,
+		// failed logins are kept in memory, so login tests need no stubs for them
+		LoginMaxFailures:        3,
+		LoginMaxFailuresPerIP:   10,
+		LoginLockoutDuration:    time.Minute,
+		LoginMaxLockoutDuration: time.Hour,
 	}
 
-	server, err := NewServer(config, store)
+	// access tokens of mock users pass the password change check, unless the test
+	// set up GetUserPasswordChangedAt itself before creating the server
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().
+			GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(time.Time{}, nil)
+	}
+
+	loginLockout, err := lockout.New(config, store)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, loginLockout)
 	require.NoError(t, err)
 	return server
 }
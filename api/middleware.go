@@ -1,12 +1,14 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
 	"github.com/lamdangtung/golang-sample-bank/token"
 )
 
@@ -16,7 +18,9 @@ const (
 	authoriztionPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware verifies the bearer access token. Tokens issued before their user last changed
+// their password are rejected, which costs one query per request.
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -45,8 +49,51 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		// scoped tokens, such as refresh tokens or the one of a pending two-factor login, are not access tokens
+		if err := payload.CheckScope(""); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		passwordChangedAt, err := store.GetUserPasswordChangedAt(ctx, payload.Username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(token.ErrInvalidToken))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if err := payload.CheckPasswordChangedAt(passwordChangedAt); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
 
 		ctx.Set(authoriztionPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// requireRole only lets through callers whose access token carries one of the given roles.
+// It must run after authMiddleware.
+func requireRole(roles ...db.UserRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+		if !hasRole(authPayload, roles...) {
+			err := fmt.Errorf("role %q is not allowed, requires one of %v", authPayload.Role, roles)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func hasRole(authPayload *token.Payload, roles ...db.UserRole) bool {
+	for _, role := range roles {
+		if db.UserRole(authPayload.Role) == role {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,8 +10,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
 	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func addAuthorization(
@@ -20,7 +25,19 @@ func addAuthorization(
 	username string,
 	duration time.Duration,
 ) {
-	accessToken, accessPayload, err := tokenMaker.CreateToken(username, duration)
+	addRoleAuthorization(t, request, tokenMaker, authorizationType, username, db.UserRoleDepositor, duration)
+}
+
+func addRoleAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	authorizationType string,
+	username string,
+	role db.UserRole,
+	duration time.Duration,
+) {
+	accessToken, accessPayload, err := tokenMaker.CreateToken(username, string(role), duration)
 	require.NoError(t, err)
 	require.NotNil(t, accessPayload)
 
@@ -32,6 +49,7 @@ func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -43,6 +61,17 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateScopedToken("user", string(db.UserRoleDepositor), token.ScopeRefresh, time.Hour)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authoriztionTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "UnAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
@@ -79,15 +108,84 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "PasswordChangedBeforeIssue",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, "user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(time.Now().Add(-time.Hour), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PasswordChangedAfterIssue",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, "user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ string) (time.Time, error) {
+						return time.Now(), nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), token.ErrRevokedToken.Error())
+			},
+		},
+		{
+			name: "UserNotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, "user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(time.Time{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, "user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(time.Time{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
 		tc := testCases[i]
 		t.Run(tc.name, func(t *testing.T) {
-			server := NewTestServer(t, nil)
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			server := NewTestServer(t, store)
 
 			authPath := "/auth"
-			server.router.GET(authPath, authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+			server.router.GET(authPath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
 				ctx.JSON(http.StatusOK, gin.H{})
 			})
 
@@ -101,3 +199,42 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireRoleMiddleware(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         db.UserRole
+		expectedCode int
+	}{
+		{name: "Depositor", role: db.UserRoleDepositor, expectedCode: http.StatusForbidden},
+		{name: "Banker", role: db.UserRoleBanker, expectedCode: http.StatusOK},
+		{name: "Admin", role: db.UserRoleAdmin, expectedCode: http.StatusOK},
+		{name: "NoRole", role: "", expectedCode: http.StatusForbidden},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server := NewTestServer(t, mockdb.NewMockStore(ctrl))
+
+			authPath := "/staff"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.store),
+				requireRole(db.UserRoleBanker, db.UserRoleAdmin),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, "user", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
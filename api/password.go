@@ -0,0 +1,167 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+)
+
+var errSamePassword = errors.New("new password must be different from the current one")
+
+type changePasswordResponse struct {
+	User            userResponse `json:"user"`
+	RevokedSessions int64        `json:"revoked_sessions"`
+}
+
+func newChangePasswordResponse(result db.ChangePasswordTxResult) changePasswordResponse {
+	return changePasswordResponse{
+		User:            NewUserResponse(result.User),
+		RevokedSessions: result.RevokedSessions,
+	}
+}
+
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// changePassword replaces the password of the caller. All their sessions are blocked and
+// their access tokens, including the one of this request, stop working.
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := util.CheckPassword(req.OldPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if util.CheckPassword(req.NewPassword, user.HashedPassword) == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errSamePassword))
+		return
+	}
+
+	hashedPassword, err := util.HashedPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		Username:       user.Username,
+		HashedPassword: hashedPassword,
+		ChangedAt:      time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChangePasswordResponse(result))
+}
+
+type requestPasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// requestPasswordReset mails a single use reset code to the owner of an email. The response
+// is the same whether the email belongs to a user or not, so that it cannot be used to find users.
+func (server *Server) requestPasswordReset(ctx *gin.Context) {
+	var req requestPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rsp := gin.H{
+		"message": "if the email belongs to a user, a password reset code has been sent to it",
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusAccepted, rsp)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	secretCode, err := util.RandomSecret(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reset, err := server.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		Username:   user.Username,
+		SecretCode: secretCode,
+		ExpiredAt:  time.Now().Add(server.config.PasswordResetDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	msg := mail.NewPasswordResetMessage(user.Email, user.FullName, reset.ID, reset.SecretCode, reset.ExpiredAt)
+	if err := server.mailer.Send(ctx, msg); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, rsp)
+}
+
+type confirmPasswordResetRequest struct {
+	ResetID     int64  `json:"reset_id" binding:"required,min=1"`
+	SecretCode  string `json:"secret_code" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// confirmPasswordReset sets a new password with a code sent by requestPasswordReset
+func (server *Server) confirmPasswordReset(ctx *gin.Context) {
+	var req confirmPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := util.HashedPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		ResetID:        req.ResetID,
+		SecretCode:     req.SecretCode,
+		HashedPassword: hashedPassword,
+		ChangedAt:      time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidPasswordReset) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChangePasswordResponse(result))
+}
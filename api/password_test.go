@@ -0,0 +1,374 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangePasswordAPI(t *testing.T) {
+	user, password := createRandomUser(t)
+	newPassword := util.RandomPassword()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"old_password": password, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ChangePasswordTxParams) (db.ChangePasswordTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						require.WithinDuration(t, time.Now(), arg.ChangedAt, time.Second)
+
+						changed := user
+						changed.HashedPassword = arg.HashedPassword
+						changed.PasswordChangedAt = arg.ChangedAt
+						return db.ChangePasswordTxResult{User: changed, RevokedSessions: 2}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp changePasswordResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, rsp.User.Username)
+				require.NotZero(t, rsp.User.PasswordChangedAt)
+				require.Equal(t, int64(2), rsp.RevokedSessions)
+			},
+		},
+		{
+			name: "WrongOldPassword",
+			body: gin.H{"old_password": "wrong-password", "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SamePassword",
+			body: gin.H{"old_password": password, "new_password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ShortNewPassword",
+			body: gin.H{"old_password": password, "new_password": "abc"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"old_password": password, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangePasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestChangePasswordRevokesAccessToken(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserPasswordChangedAt(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ string) (time.Time, error) {
+			return time.Now(), nil
+		})
+	store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/sessions", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Contains(t, recorder.Body.String(), token.ErrRevokedToken.Error())
+}
+
+func TestRequestPasswordResetAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePasswordResetParams) (db.PasswordReset, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.GreaterOrEqual(t, len(arg.SecretCode), 32)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiredAt, time.Second)
+						return db.PasswordReset{
+							ID:         7,
+							Username:   arg.Username,
+							SecretCode: arg.SecretCode,
+							ExpiredAt:  arg.ExpiredAt,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{user.Email}, messages[0].To)
+				require.Contains(t, messages[0].Body, "Reset ID: 7\n")
+			},
+		},
+		{
+			name: "UnknownEmail",
+			body: gin.H{"email": util.RandomEmail()},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"email": "not-an-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password-reset/request", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.mailer.(*mail.MemoryMailer).Messages())
+		})
+	}
+}
+
+func TestConfirmPasswordResetAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	code := util.RandomString(32)
+	newPassword := util.RandomPassword()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"reset_id": 7, "secret_code": code, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ResetPasswordTxParams) (db.ChangePasswordTxResult, error) {
+						require.Equal(t, int64(7), arg.ResetID)
+						require.Equal(t, code, arg.SecretCode)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						return db.ChangePasswordTxResult{User: user, RevokedSessions: 1}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp changePasswordResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, rsp.User.Username)
+				require.Equal(t, int64(1), rsp.RevokedSessions)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{"reset_id": 7, "secret_code": code, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangePasswordTxResult{}, db.ErrInvalidPasswordReset)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: gin.H{"reset_id": 7, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"reset_id": 7, "secret_code": code, "new_password": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangePasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password-reset/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/reconcile"
+)
+
+type reconcileRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json text"`
+}
+
+// reconcileLedger scans the whole ledger, it is meant for admins and scheduled checks
+func (server *Server) reconcileLedger(ctx *gin.Context) {
+	var req reconcileRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	report, err := reconcile.Run(ctx, server.store)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Format == "text" {
+		var buf bytes.Buffer
+		if err := report.WriteSummary(&buf); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReconcileLedgerAPI(t *testing.T) {
+	result := db.ReconcileTxResult{
+		Counts: db.CountLedgerRow{Accounts: 2, Entries: 3, Transfers: 1},
+		BalanceDrifts: []db.ListBalanceDriftsRow{
+			{ID: 1, Owner: util.RandomOwner(), Currency: money.USD, Balance: 500, EntriesTotal: 0},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		role          db.UserRole
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "JSON",
+			role: db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileTx(gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, float64(3), rsp["entries_checked"])
+				drifts := rsp["balance_drifts"].([]any)
+				require.Len(t, drifts, 1)
+				require.Equal(t, "5.00", drifts[0].(map[string]any)["drift"])
+			},
+		},
+		{
+			name:  "Text",
+			role:  db.UserRoleAdmin,
+			query: "?format=text",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileTx(gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+				require.Contains(t, recorder.Body.String(), "FAILED: 1 issues found")
+			},
+		},
+		{
+			name:  "InvalidFormat",
+			role:  db.UserRoleAdmin,
+			query: "?format=xml",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Banker",
+			role: db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			role: db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileTx(gomock.Any()).
+					Times(1).
+					Return(db.ReconcileTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/reconciliation"+tc.query, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, util.RandomOwner(), tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -6,7 +6,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/fx"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/statement"
 	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/lamdangtung/golang-sample-bank/util"
 )
@@ -16,19 +22,49 @@ type Server struct {
 	store      db.Store
 	router     *gin.Engine
 	tokenMaker token.Maker
+	fxService  *fx.Service
+	cursors    cursor.Codec
+	statements *statement.Registry
+	mailer     mail.Mailer
+	mfa        *mfa.Service
+	lockout    *lockout.Tracker
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+// NewServer creates the HTTP server. loginLockout is shared with the gRPC servers, so that
+// failed logins count the same whichever server they hit.
+func NewServer(config util.Config, store db.Store, loginLockout *lockout.Tracker) (*Server, error) {
 	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
-	server := &Server{store: store, tokenMaker: tokenMaker, config: config}
+	mailer, err := mail.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
+	}
+	thresholds, err := mfa.ParseTransferThresholds(config.MFATransferThresholds)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse MFA transfer thresholds: %w", err)
+	}
+	server := &Server{
+		store:      store,
+		tokenMaker: tokenMaker,
+		config:     config,
+		fxService:  fx.NewService(store),
+		cursors:    cursor.NewCodec(config.TokenSymmetricKey),
+		statements: statement.DefaultRegistry(),
+		mailer:     mailer,
+		mfa:        mfa.NewService(store, config.TOTPIssuer, thresholds),
+		lockout:    loginLockout,
+	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
 	}
 	server.SetupRouter()
+	// gin trusts every proxy by default, which lets any client pick its IP with X-Forwarded-For
+	if err := server.router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("cannot set trusted proxies: %w", err)
+	}
 	return server, nil
 }
 
@@ -37,19 +73,59 @@ func (server *Server) SetupRouter() {
 	/// User
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login/mfa", server.loginUserMFA)
+	router.GET("/users/verify-email", server.verifyEmail)
+	router.POST("/users/password-reset/request", server.requestPasswordReset)
+	router.POST("/users/password-reset/confirm", server.confirmPasswordReset)
 	router.POST("/tokens/renew-access", server.renewAccessToken)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+	idempotent := idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL)
+	viewAccount := accountMiddleware(server.store, accountView)
+	freezeAccount := accountMiddleware(server.store, accountFreeze)
+	ownAccount := accountMiddleware(server.store, accountManage)
+
+	/// Session
+	authRoutes.GET("/sessions", server.listSessions)
+	authRoutes.DELETE("/sessions/:id", server.revokeSession)
+	authRoutes.POST("/users/logout-all", server.logoutAll)
+	authRoutes.POST("/users/password", server.changePassword)
+	authRoutes.GET("/users/me", server.getMe)
+	authRoutes.PATCH("/users/me", server.updateMe)
+	authRoutes.POST("/users/totp", server.enrollTOTP)
+	authRoutes.POST("/users/totp/confirm", server.confirmTOTP)
+	authRoutes.POST("/users/totp/disable", server.disableTOTP)
 
 	/// Account
-	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.POST("/accounts", idempotent, server.createAccount)
+	authRoutes.GET("/accounts/:id", viewAccount, server.getAccount)
 	authRoutes.GET("/accounts", server.listAccounts)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
-	authRoutes.PUT("/accounts/:id", server.updateAccount)
+	authRoutes.POST("/accounts/:id/freeze", freezeAccount, server.freezeAccount)
+	authRoutes.POST("/accounts/:id/unfreeze", requireRole(staffRoles...), viewAccount, server.unfreezeAccount)
+	authRoutes.POST("/accounts/:id/close", ownAccount, server.closeAccount)
+	authRoutes.GET("/accounts/:id/entries", viewAccount, server.listAccountEntries)
+	authRoutes.GET("/accounts/:id/transfers", viewAccount, server.listAccountTransfers)
+	authRoutes.GET("/accounts/:id/statement", viewAccount, server.getAccountStatement)
 
 	/// Transfer
-	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers", idempotent, server.createTransfer)
+	authRoutes.GET("/transfers/:id", server.getTransfer)
+	authRoutes.POST("/transfers/:id/reverse", idempotent, server.reverseTransfer)
+
+	/// Exchange rate
+	authRoutes.GET("/exchange-rates", server.getExchangeRate)
+
+	/// Admin
+	adminRoutes := router.Group("/admin").Use(authMiddleware(server.tokenMaker, server.store), requireRole(db.UserRoleAdmin))
+	adminRoutes.GET("/users/:username", server.getUser)
+	adminRoutes.PUT("/users/:username/role", server.updateUserRole)
+	adminRoutes.POST("/users/:username/unlock", server.unlockUser)
+	adminRoutes.POST("/exchange-rates", server.uploadExchangeRates)
+	adminRoutes.POST("/accounts/:id/adjustments", idempotent, server.createAdjustment)
+	adminRoutes.PUT("/accounts/:id/overdraft-limit", server.updateOverdraftLimit)
+	adminRoutes.GET("/adjustments", server.listAdjustments)
+	adminRoutes.GET("/reconciliation", server.reconcileLedger)
+	adminRoutes.GET("/tx-stats", server.getTxStats)
 	server.router = router
 }
 
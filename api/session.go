@@ -0,0 +1,139 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+)
+
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIp  string    `json:"client_ip"`
+	ExpiredAt time.Time `json:"expired_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// newSessionResponse leaves out the refresh token, which must never be sent back to the client
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		ExpiredAt: session.ExpiredAt,
+		CreatedAt: session.CreatedAt,
+	}
+}
+
+type listSessionsRequest struct {
+	pageRequest
+}
+
+// sessionCursor is the position of the last session of a page, newest sessions come first
+type sessionCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (server *Server) listSessions(ctx *gin.Context) {
+	var req listSessionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	scope := "sessions:" + authPayload.Username
+
+	arg := db.ListActiveSessionsParams{
+		Username: authPayload.Username,
+		Limit:    req.pageLimit() + 1,
+	}
+	if len(req.After) > 0 {
+		var after sessionCursor
+		if err := server.cursors.Decode(scope, req.After, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.AfterCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		arg.AfterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+
+	sessions, err := server.store.ListActiveSessions(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sessions, more := cursor.SplitPage(sessions, req.pageLimit())
+	rsp := pageResponse[sessionResponse]{
+		Items: make([]sessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		rsp.Items = append(rsp.Items, newSessionResponse(session))
+	}
+
+	if more {
+		last := sessions[len(sessions)-1]
+		rsp.NextCursor, err = server.cursors.Encode(scope, sessionCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type revokeSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+func (server *Server) revokeSession(ctx *gin.Context) {
+	var req revokeSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+
+	// sessions of other users are reported as not found so that their IDs cannot be probed
+	session, err := server.store.BlockSession(ctx, db.BlockSessionParams{
+		ID:       uuid.MustParse(req.ID),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
+
+// logoutAll blocks every session of the caller. Access tokens that were already issued
+// stay valid until they expire, but none of the sessions can renew them anymore.
+func (server *Server) logoutAll(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+
+	revoked, err := server.store.BlockUserSessions(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"revoked_sessions": revoked,
+	})
+}
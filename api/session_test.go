@@ -0,0 +1,201 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	session := randomSession(user.Username)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ListOK",
+			method: http.MethodGet,
+			url:    "/sessions",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Eq(db.ListActiveSessionsParams{
+						Username: user.Username,
+						Limit:    cursor.DefaultLimit + 1,
+					})).
+					Times(1).
+					Return([]db.Session{session}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), session.RefreshToken)
+
+				var rsp pageResponse[sessionResponse]
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Items, 1)
+				require.Equal(t, session.ID, rsp.Items[0].ID)
+				require.Equal(t, session.UserAgent, rsp.Items[0].UserAgent)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:   "ListNoAuthorization",
+			method: http.MethodGet,
+			url:    "/sessions",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "RevokeOK",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/sessions/%s", session.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.BlockSessionParams{
+					ID:       session.ID,
+					Username: user.Username,
+				}
+				blocked := session
+				blocked.IsBlocked = true
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(blocked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "RevokeNotFound",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/sessions/%s", session.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, util.RandomUsername(), time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "RevokeInvalidID",
+			method: http.MethodDelete,
+			url:    "/sessions/abc",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "LogoutAllOK",
+			method: http.MethodPost,
+			url:    "/users/logout-all",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"revoked_sessions": 3}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "LogoutAllInternalError",
+			method: http.MethodPost,
+			url:    "/users/logout-all",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomSession(username string) db.Session {
+	id := uuid.New()
+	return db.Session{
+		ID:           id,
+		Username:     username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "Mozilla/5.0",
+		ClientIp:     "127.0.0.1",
+		ExpiredAt:    time.Now().Add(time.Hour),
+		CreatedAt:    time.Now(),
+		FamilyID:     id,
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/statement"
+)
+
+const defaultStatementFormat = "csv"
+
+// statementRequest selects the period [from, to) of a statement, to defaults to now
+type statementRequest struct {
+	From   time.Time `form:"from" binding:"required"`
+	To     time.Time `form:"to" binding:"omitempty,gtfield=From"`
+	Format string    `form:"format"`
+}
+
+func (server *Server) getAccountStatement(ctx *gin.Context) {
+	account := ctx.MustGet(accountKey).(db.Account)
+
+	var req statementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+		if !req.To.After(req.From) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("from must be in the past")))
+			return
+		}
+	}
+	// a statement covers at most a year, which bounds the entries read and rendered at once
+	if req.To.After(req.From.AddDate(1, 0, 0)) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("statement period must be at most one year")))
+		return
+	}
+	if len(req.Format) == 0 {
+		req.Format = defaultStatementFormat
+	}
+
+	renderer, err := server.statements.Lookup(req.Format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.StatementTx(ctx, db.StatementTxParams{
+		AccountID: account.ID,
+		FromTime:  req.From,
+		ToTime:    req.To,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	stmt, err := statement.Build(result, req.From, req.To)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// render into a buffer first so a failing renderer still gets a proper error response
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, stmt); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s-%s.%s", account.ID,
+		req.From.UTC().Format("20060102"), req.To.UTC().Format("20060102"), renderer.Format())
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, renderer.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetAccountStatementAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = money.USD
+	account.Balance = 12000
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	result := db.StatementTxResult{
+		Account:        account,
+		OpeningBalance: 10000,
+		ClosingBalance: 11500,
+		Entries: []db.ListStatementEntriesRow{
+			{
+				Entry:                 db.Entry{ID: 1, AccountID: account.ID, Amount: 2000, CreatedAt: from.Add(time.Hour), TransferID: sql.NullInt64{Int64: 7, Valid: true}},
+				TransferFromAccountID: sql.NullInt64{Int64: account.ID + 1, Valid: true},
+				TransferToAccountID:   sql.NullInt64{Int64: account.ID, Valid: true},
+			},
+			{
+				Entry:            db.Entry{ID: 2, AccountID: account.ID, Amount: -500, CreatedAt: from.Add(2 * time.Hour)},
+				AdjustmentReason: sql.NullString{String: "fee", Valid: true},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "CSV",
+			username: user.Username,
+			query: url.Values{
+				"from": {from.Format(time.RFC3339)},
+				"to":   {to.Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.StatementTxParams{
+					AccountID: account.ID,
+					FromTime:  from,
+					ToTime:    to,
+				}
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Equal(t, fmt.Sprintf(`attachment; filename="statement-%d-20240101-20240201.csv"`, account.ID),
+					recorder.Header().Get("Content-Disposition"))
+
+				rows, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, rows, 5)
+				require.Equal(t, []string{"Opening balance", "100.00"}, []string{rows[1][3], rows[1][5]})
+				require.Equal(t, []string{"7", "20.00", "120.00"}, []string{rows[2][2], rows[2][4], rows[2][5]})
+				require.Equal(t, []string{"-5.00", "115.00"}, []string{rows[3][4], rows[3][5]})
+				require.Equal(t, []string{"Closing balance", "115.00"}, []string{rows[4][3], rows[4][5]})
+			},
+		},
+		{
+			name:     "OFX",
+			username: user.Username,
+			query: url.Values{
+				"from":   {from.Format(time.RFC3339)},
+				"to":     {to.Format(time.RFC3339)},
+				"format": {"ofx"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/x-ofx", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), "<BALAMT>115.00</BALAMT>")
+			},
+		},
+		{
+			name:     "PDF",
+			username: user.Username,
+			query: url.Values{
+				"from":   {from.Format(time.RFC3339)},
+				"to":     {to.Format(time.RFC3339)},
+				"format": {"PDF"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.True(t, strings.HasPrefix(recorder.Body.String(), "%PDF-"))
+			},
+		},
+		{
+			name:     "Banker",
+			username: util.RandomOwner(),
+			role:     db.UserRoleBanker,
+			query:    url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Forbidden",
+			username: util.RandomOwner(),
+			query:    url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingFrom",
+			username: user.Username,
+			query:    url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidDateRange",
+			username: user.Username,
+			query: url.Values{
+				"from": {to.Format(time.RFC3339)},
+				"to":   {from.Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "PeriodTooLong",
+			username: user.Username,
+			query: url.Values{
+				"from": {from.Format(time.RFC3339)},
+				"to":   {from.AddDate(1, 0, 1).Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "DefaultToIsTooLong",
+			username: user.Username,
+			query:    url.Values{"from": {from.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnsupportedFormat",
+			username: user.Username,
+			query: url.Values{
+				"from":   {from.Format(time.RFC3339)},
+				"format": {"xlsx"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Unreconciled",
+			username: user.Username,
+			query:    url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				unreconciled := result
+				unreconciled.ClosingBalance = 12000
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(unreconciled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			username: user.Username,
+			query:    url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.StatementTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				Return(account, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/statement?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
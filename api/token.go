@@ -2,11 +2,15 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
 )
 
 type renewAccessTokenRequest struct {
@@ -14,10 +18,15 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiredAt time.Time `json:"access_token_expired_at"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiredAt  time.Time `json:"access_token_expired_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiredAt time.Time `json:"refresh_token_expired_at"`
 }
 
+// renewAccessToken exchanges a refresh token for a new access token and a new refresh token.
+// Each refresh token can only be exchanged once: presenting it again means it has leaked,
+// so every session descending from the same login is blocked.
 func (server *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -30,6 +39,10 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
+	if err := refreshPayload.CheckScope(token.ScopeRefresh); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
 	session, err := server.store.GetSession(ctx, refreshPayload.ID)
 	if err != nil {
@@ -42,33 +55,87 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	if session.Username != refreshPayload.Username {
-		err := fmt.Errorf("Refresh token not matched")
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if session.Username != refreshPayload.Username || session.RefreshToken != req.RefreshToken {
+		err := fmt.Errorf("refresh token not matched")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
 	if session.IsBlocked {
-		err := fmt.Errorf("Session is blocked")
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		err := fmt.Errorf("session is blocked")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
 	if session.ExpiredAt.Before(time.Now()) {
 		err := fmt.Errorf("Session is expired")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if session.RotatedAt.Valid {
+		server.refreshTokenReused(ctx, session)
+		return
+	}
+
+	// the role is read again so that promotions and demotions apply on the next renewal
+	user, err := server.store.GetUser(ctx, session.Username)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(session.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeRefresh, server.config.RefreshTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	newSession, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		ParentID: session.ID,
+		NewSession: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     newRefreshPayload.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlocked:    false,
+			ExpiredAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			server.refreshTokenReused(ctx, session)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	res := renewAccessTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiredAt: accessPayload.ExpiredAt,
+		AccessToken:           accessToken,
+		AccessTokenExpiredAt:  accessPayload.ExpiredAt,
+		RefreshToken:          newSession.RefreshToken,
+		RefreshTokenExpiredAt: newSession.ExpiredAt,
 	}
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (server *Server) refreshTokenReused(ctx *gin.Context, session db.Session) {
+	blocked, err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	log.Printf("security event: refresh token reuse for user %s from %s (%s), session %s, %d sessions of family %s blocked",
+		session.Username, ctx.ClientIP(), ctx.Request.UserAgent(), session.ID, blocked, session.FamilyID)
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(db.ErrRefreshTokenReused))
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		updateSession func(session *db.Session)
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session)
+	}{
+		{
+			name:          "OK",
+			updateSession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.Session, error) {
+						require.Equal(t, session.ID, arg.ParentID)
+						require.Equal(t, user.Username, arg.NewSession.Username)
+						require.NotEqual(t, session.RefreshToken, arg.NewSession.RefreshToken)
+						return db.Session{
+							ID:           arg.NewSession.ID,
+							Username:     arg.NewSession.Username,
+							RefreshToken: arg.NewSession.RefreshToken,
+							ExpiredAt:    arg.NewSession.ExpiredAt,
+							FamilyID:     session.FamilyID,
+						}, nil
+					})
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp renewAccessTokenResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.NotEqual(t, session.RefreshToken, rsp.RefreshToken)
+			},
+		},
+		{
+			name: "BlockedSession",
+			updateSession: func(session *db.Session) {
+				session.IsBlocked = true
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredSession",
+			updateSession: func(session *db.Session) {
+				session.ExpiredAt = time.Now().Add(-time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshTokenMismatch",
+			updateSession: func(session *db.Session) {
+				session.RefreshToken = util.RandomString(32)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RotatedTokenReused",
+			updateSession: func(session *db.Session) {
+				session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:          "ConcurrentRotation",
+			updateSession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, db.ErrRefreshTokenReused)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:          "InternalError",
+			updateSession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := NewTestServer(t, store)
+			server.config.RefreshTokenDuration = time.Hour
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeRefresh, time.Hour)
+			require.NoError(t, err)
+
+			session := randomSession(user.Username)
+			session.ID = refreshPayload.ID
+			session.RefreshToken = refreshToken
+			tc.updateSession(&session)
+
+			store.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).
+				Times(1).
+				Return(session, nil)
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew-access", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, session)
+		})
+	}
+}
+
+func TestRenewAccessTokenRejectsAccessToken(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), time.Minute)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(gin.H{"refresh_token": accessToken})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/tokens/renew-access", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/token"
+)
+
+// mfaErrorStatus maps errors of the mfa service to HTTP status codes
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, mfa.ErrAlreadyEnabled), errors.Is(err, mfa.ErrNotEnabled), errors.Is(err, mfa.ErrNotEnrolled):
+		return http.StatusConflict
+	case errors.Is(err, mfa.ErrInvalidCode), errors.Is(err, mfa.ErrCodeRequired):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// enrollTOTP starts the two-factor enrollment of the caller. The provisioning URI is meant to be
+// shown as a QR code, two-factor authentication is enabled once confirmTOTP accepts a code.
+func (server *Server) enrollTOTP(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+
+	enrollment, err := server.mfa.Enroll(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+type totpCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type confirmTOTPResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// confirmTOTP enables two-factor authentication with a first code and returns the recovery
+// codes, which are only shown this once
+func (server *Server) confirmTOTP(ctx *gin.Context) {
+	var req totpCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	var recoveryCodes []string
+	ok := server.requireTOTPCode(ctx, authPayload.Username, func() (err error) {
+		recoveryCodes, err = server.mfa.Confirm(ctx, authPayload.Username, req.Code)
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, confirmTOTPResponse{RecoveryCodes: recoveryCodes})
+}
+
+// disableTOTP turns two-factor authentication off with a code or a recovery code
+func (server *Server) disableTOTP(ctx *gin.Context) {
+	var req totpCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	ok := server.requireTOTPCode(ctx, authPayload.Username, func() error {
+		return server.mfa.Disable(ctx, authPayload.Username, req.Code)
+	})
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"totp_enabled": false})
+}
+
+type loginUserMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a TOTP code or a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+// loginUserMFA is the second step of the login of users with two-factor authentication, it
+// exchanges the MFA token given by loginUser and a code for access and refresh tokens
+func (server *Server) loginUserMFA(ctx *gin.Context) {
+	var req loginUserMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.MFAToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if err := payload.CheckScope(token.ScopeMFAPending); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	// codes are guessed more easily than passwords, so they count towards the same lockout
+	reservation, ok := server.reserveLogin(ctx, payload.Username)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// a login started with the previous password cannot be completed
+	if err := payload.CheckPasswordChangedAt(user.PasswordChangedAt); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if err := server.mfa.VerifyOrRecover(ctx, user.Username, req.Code); err != nil {
+		if mfaErrorStatus(err) == http.StatusInternalServerError {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if !server.refundLogin(ctx, reservation) {
+		return
+	}
+
+	server.startSession(ctx, user)
+}
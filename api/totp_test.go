@@ -0,0 +1,362 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoginUserRequiresMFA(t *testing.T) {
+	user, password := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(db.UserTotp{Username: user.Username, ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"username": user.Username, "password": password})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp loginMFARequiredResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.True(t, rsp.MFARequired)
+
+	payload, err := server.tokenMaker.VerifyToken(rsp.MFAToken)
+	require.NoError(t, err)
+	require.Equal(t, token.ScopeMFAPending, payload.Scope)
+
+	// the MFA token is not an access token
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/sessions", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, authoriztionTypeBearer+" "+rsp.MFAToken)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestLoginUserMFAAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	userTotp := db.UserTotp{
+		Username:    user.Username,
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	validCode := func(t *testing.T) string {
+		code, err := mfa.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		return code
+	}
+	mfaToken := func(t *testing.T, tokenMaker token.Maker) string {
+		mfaToken, _, err := tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeMFAPending, time.Minute)
+		require.NoError(t, err)
+		return mfaToken
+	}
+
+	testCases := []struct {
+		name          string
+		body          func(t *testing.T, tokenMaker token.Maker) gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"mfa_token": mfaToken(t, tokenMaker), "code": validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userTotp, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(userTotp, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.Equal(t, user.Username, rsp.User.Username)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"mfa_token": mfaToken(t, tokenMaker), "code": "abcde-fghij"}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userTotp, nil)
+				store.EXPECT().UseTotpRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(db.TotpRecoveryCode{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenInsteadOfMFAToken",
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, string(user.Role), time.Minute)
+				require.NoError(t, err)
+				return gin.H{"mfa_token": accessToken, "code": validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "PasswordChangedSinceLogin",
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"mfa_token": mfaToken(t, tokenMaker), "code": validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changed.PasswordChangedAt = time.Now().Add(time.Minute)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(changed, nil)
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body(t, server.tokenMaker))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEnrollAndConfirmTOTPAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	var pending db.UserTotp
+	store.EXPECT().
+		UpsertPendingUserTotp(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.UpsertPendingUserTotpParams) (db.UserTotp, error) {
+			require.Equal(t, user.Username, arg.Username)
+			pending = db.UserTotp{Username: arg.Username, Secret: arg.Secret}
+			return pending, nil
+		})
+	store.EXPECT().
+		GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ string) (db.UserTotp, error) {
+			return pending, nil
+		})
+	store.EXPECT().
+		ConfirmTOTPTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ConfirmTOTPTxParams) (db.UserTotp, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Len(t, arg.HashedRecoveryCodes, 10)
+			return pending, nil
+		})
+
+	server := NewTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/totp", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var enrollment mfa.Enrollment
+	err = json.Unmarshal(recorder.Body.Bytes(), &enrollment)
+	require.NoError(t, err)
+	require.Equal(t, pending.Secret, enrollment.Secret)
+	require.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/")
+
+	code, err := mfa.GenerateCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	data, err := json.Marshal(gin.H{"code": code})
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/users/totp/confirm", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp confirmTOTPResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.Len(t, rsp.RecoveryCodes, 10)
+}
+
+func TestEnrollTOTPAlreadyEnabled(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().UpsertPendingUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(db.UserTotp{}, sql.ErrNoRows)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/totp", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestTOTPCodeLockout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		url         string
+		confirmedAt sql.NullTime
+	}{
+		{
+			name: "Confirm",
+			url:  "/users/totp/confirm",
+		},
+		{
+			name:        "Disable",
+			url:         "/users/totp/disable",
+			confirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			user, _ := createRandomUser(t)
+			secret, err := mfa.GenerateSecret()
+			require.NoError(t, err)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+				AnyTimes().
+				Return(db.UserTotp{Username: user.Username, Secret: secret, ConfirmedAt: tc.confirmedAt}, nil)
+			store.EXPECT().ConfirmTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+
+			server := NewTestServer(t, store)
+			sendCode := func(code string) *httptest.ResponseRecorder {
+				data, err := json.Marshal(gin.H{"code": code})
+				require.NoError(t, err)
+
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewReader(data))
+				require.NoError(t, err)
+				addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+				server.router.ServeHTTP(recorder, request)
+				return recorder
+			}
+
+			code, err := mfa.GenerateCode(secret, time.Now())
+			require.NoError(t, err)
+
+			for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+				recorder := sendCode(wrongTOTPCode(code))
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			}
+
+			// once locked out, not even the right code is checked
+			recorder := sendCode(code)
+			require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestTOTPCodeConflictIsNotCounted(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+		AnyTimes().
+		Return(db.UserTotp{}, sql.ErrNoRows)
+
+	server := NewTestServer(t, store)
+	data, err := json.Marshal(gin.H{"code": "123456"})
+	require.NoError(t, err)
+
+	// disabling two-factor authentication that was never enabled does not check the code
+	for i := 0; i <= int(server.config.LoginMaxFailures); i++ {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/users/totp/disable", bytes.NewReader(data))
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+	}
+}
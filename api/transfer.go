@@ -4,18 +4,110 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/fx"
+	"github.com/lamdangtung/golang-sample-bank/money"
 	"github.com/lamdangtung/golang-sample-bank/token"
 )
 
 type createTransferRequest struct {
 	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
 	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	Amount        string `json:"amount" binding:"required"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	// TOTPCode is required from users with two-factor authentication above MFA_TRANSFER_THRESHOLDS
+	TOTPCode string `json:"totp_code"`
+}
+
+const (
+	transferStatusCompleted         = "completed"
+	transferStatusPartiallyReversed = "partially_reversed"
+	transferStatusReversed          = "reversed"
+)
+
+type transferResponse struct {
+	ID            int64        `json:"id"`
+	FromAccountID int64        `json:"from_account_id"`
+	ToAccountID   int64        `json:"to_account_id"`
+	Amount        money.Amount `json:"amount"`
+	ToAmount      money.Amount `json:"to_amount"`
+	ExchangeRate  string       `json:"exchange_rate"`
+	Status        string       `json:"status"`
+	// ReversedAmount is the part of Amount paid back to the sender by reversals
+	ReversedAmount money.Amount `json:"reversed_amount"`
+	ReversalOf     *int64       `json:"reversal_of,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func newTransferResponse(transfer db.Transfer, fromCurrency string, toCurrency string) transferResponse {
+	rsp := transferResponse{
+		ID:             transfer.ID,
+		FromAccountID:  transfer.FromAccountID,
+		ToAccountID:    transfer.ToAccountID,
+		Amount:         money.New(transfer.Amount, fromCurrency),
+		ToAmount:       money.New(transfer.ToAmount, toCurrency),
+		ExchangeRate:   transfer.ExchangeRate,
+		Status:         transferStatus(transfer),
+		ReversedAmount: money.New(transfer.ReversedAmount, fromCurrency),
+		CreatedAt:      transfer.CreatedAt,
+	}
+	if transfer.ReversalOf.Valid {
+		rsp.ReversalOf = &transfer.ReversalOf.Int64
+	}
+	return rsp
+}
+
+func transferStatus(transfer db.Transfer) string {
+	switch {
+	case transfer.ReversedAmount == 0:
+		return transferStatusCompleted
+	case transfer.ReversedAmount < transfer.Amount:
+		return transferStatusPartiallyReversed
+	default:
+		return transferStatusReversed
+	}
+}
+
+type entryResponse struct {
+	ID        int64        `json:"id"`
+	AccountID int64        `json:"account_id"`
+	Amount    money.Amount `json:"amount"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func newEntryResponse(entry db.Entry, currency string) entryResponse {
+	return entryResponse{
+		ID:        entry.ID,
+		AccountID: entry.AccountID,
+		Amount:    money.New(entry.Amount, currency),
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+type transferTxResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) transferTxResponse {
+	fromCurrency := result.FromAccount.Currency
+	toCurrency := result.ToAccount.Currency
+
+	return transferTxResponse{
+		Transfer:    newTransferResponse(result.Transfer, fromCurrency, toCurrency),
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount:   newAccountResponse(result.ToAccount),
+		FromEntry:   newEntryResponse(result.FromEntry, fromCurrency),
+		ToEntry:     newEntryResponse(result.ToEnTry, toCurrency),
+	}
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -25,19 +117,35 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	amount, err := money.Parse(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !amount.IsPositive() {
+		err := errors.New("amount must be positive")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
 		return
 	}
 
 	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if err := authorizeAccount(authPayload, fromAccount, accountManage); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	if !server.requireVerifiedEmail(ctx, authPayload.Username) {
+		return
+	}
+	if !server.requireTransferCode(ctx, authPayload.Username, amount, req.TOTPCode) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	toAccount, valid := server.loadAccount(ctx, req.ToAccountID)
 	if !valid {
 		return
 	}
@@ -45,18 +153,64 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	arg := db.TransferTxParams{
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		Amount:        amount.Units(),
+	}
+
+	if toAccount.Currency != fromAccount.Currency {
+		conversion, err := server.fxService.Convert(ctx, amount, toAccount.Currency)
+		if err != nil {
+			ctx.JSON(conversionErrorStatus(err), errorResponse(err))
+			return
+		}
+		arg.ToAmount = conversion.ConvertedAmount.Units()
+		arg.ExchangeRate = conversion.Rate
 	}
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, newTransferTxResponse(result))
+}
+
+var errEmailNotVerified = errors.New("email address is not verified, open the link sent to it first")
+
+// requireVerifiedEmail writes a 403 unless the user has verified their email. It reads the user
+// rather than trusting the token, so that verifying takes effect without logging in again.
+func (server *Server) requireVerifiedEmail(ctx *gin.Context, username string) bool {
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	if !user.IsEmailVerified {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+		return false
+	}
+	return true
 }
 
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
+	account, valid := server.loadAccount(ctx, accountID)
+	if !valid {
+		return account, false
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+func (server *Server) loadAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
 
@@ -69,11 +223,165 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		return account, false
 	}
 
-	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+	return account, true
+}
+
+type getTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type transferDetailResponse struct {
+	transferResponse
+	Reversals []transferResponse `json:"reversals"`
+}
+
+// getTransfer shows a transfer and its reversals to the owners of either side and to staff
+func (server *Server) getTransfer(ctx *gin.Context) {
+	var req getTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
+		return
 	}
 
-	return account, true
+	transfer, fromAccount, toAccount, valid := server.loadTransfer(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	if authorizeAccount(authPayload, fromAccount, accountView) != nil &&
+		authorizeAccount(authPayload, toAccount, accountView) != nil {
+		err := errors.New("transfer doesn't involve an account of the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	reversals, err := server.store.ListTransferReversals(ctx, sql.NullInt64{Int64: transfer.ID, Valid: true})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := transferDetailResponse{
+		transferResponse: newTransferResponse(transfer, fromAccount.Currency, toAccount.Currency),
+		Reversals:        make([]transferResponse, 0, len(reversals)),
+	}
+	// reversals go the other way, from the recipient back to the sender
+	for _, reversal := range reversals {
+		rsp.Reversals = append(rsp.Reversals, newTransferResponse(reversal, toAccount.Currency, fromAccount.Currency))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// loadTransfer loads a transfer with both of its accounts and writes an error response on failure
+func (server *Server) loadTransfer(ctx *gin.Context, transferID int64) (db.Transfer, db.Account, db.Account, bool) {
+	transfer, err := server.store.GetTransfer(ctx, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return transfer, db.Account{}, db.Account{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return transfer, db.Account{}, db.Account{}, false
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return transfer, fromAccount, db.Account{}, false
+	}
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return transfer, fromAccount, toAccount, false
+	}
+
+	return transfer, fromAccount, toAccount, true
+}
+
+type reverseTransferRequest struct {
+	// Amount is paid back to the sender in the currency of the from account,
+	// everything left to reverse when empty
+	Amount string `json:"amount"`
+}
+
+type reverseTransferResponse struct {
+	Original transferResponse   `json:"original"`
+	Reversal transferTxResponse `json:"reversal"`
+}
+
+// reverseTransfer pays a transfer back, in full or in part, with a compensating transfer from
+// the recipient to the sender. Only the recipient and admins can reverse a transfer.
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri getTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the body is optional, an empty one reverses the whole transfer
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, fromAccount, toAccount, valid := server.loadTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	if err := authorizeAccount(authPayload, toAccount, accountManage); err != nil && !hasRole(authPayload, db.UserRoleAdmin) {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.ReverseTransferTxParams{
+		TransferID:   transfer.ID,
+		ExchangeRate: "1",
+	}
+
+	if len(req.Amount) > 0 {
+		amount, err := money.Parse(req.Amount, fromAccount.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if !amount.IsPositive() {
+			err := errors.New("amount must be positive")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Amount = amount.Units()
+	}
+
+	if fromAccount.Currency != toAccount.Currency {
+		rate, err := fx.InverseRate(transfer.ExchangeRate)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.ExchangeRate = rate
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrOverReversal),
+			errors.Is(err, db.ErrReversalNotReversible),
+			errors.Is(err, db.ErrInsufficientFunds),
+			errors.Is(err, db.ErrAccountNotActive):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reverseTransferResponse{
+		Original: newTransferResponse(result.Original, fromAccount.Currency, toAccount.Currency),
+		Reversal: newTransferTxResponse(result.TransferTxResult),
+	})
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,9 @@ import (
 	"github.com/gin-gonic/gin"
 	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/money"
 	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/stretchr/testify/require"
@@ -27,10 +31,11 @@ func TestCreateTransfer(t *testing.T) {
 	account1 := randomAccount(user1.Username)
 	account2 := randomAccount(user2.Username)
 	account3 := randomAccount(user3.Username)
-	account1.Currency = util.USD
-	account2.Currency = util.USD
-	account3.Currency = util.EUR
+	account1.Currency = money.USD
+	account2.Currency = money.USD
+	account3.Currency = money.EUR
 	amount := int64(10)
+	amountInput := money.New(amount, money.USD).String()
 	testCases := []struct {
 		name          string
 		body          gin.H
@@ -43,8 +48,8 @@ func TestCreateTransfer(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"amount":          amountInput,
+				"currency":        money.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -76,8 +81,8 @@ func TestCreateTransfer(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"amount":          amountInput,
+				"currency":        money.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -104,13 +109,171 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, recorder.Code, http.StatusInternalServerError)
 			},
 		},
+		{
+			name: "CrossCurrency",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amountInput,
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).
+					Return(account1, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account3.ID)).
+					Times(1).
+					Return(account3, nil)
+
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExchangeRate{BaseCurrency: money.USD, QuoteCurrency: money.EUR, Rate: "0.95"}, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account3.ID,
+					Amount:        amount,
+					ToAmount:      9,
+					ExchangeRate:  "0.95",
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ExchangeRateNotFound",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amountInput,
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).
+					Return(account1, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account3.ID)).
+					Times(1).
+					Return(account3, nil)
+
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.ExchangeRate{}, sql.ErrNoRows)
+
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amountInput,
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).
+					Return(account1, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account2.ID)).
+					Times(1).
+					Return(account2, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, fmt.Errorf("%w: account [%d]", db.ErrInsufficientFunds, account1.ID))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "FromAccountNotOwned",
+			body: gin.H{
+				"from_account_id": account2.ID,
+				"to_account_id":   account1.ID,
+				"amount":          amountInput,
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account2.ID)).
+					Times(1).
+					Return(account2, nil)
+
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotActive",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amountInput,
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).
+					Return(account1, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account2.ID)).
+					Times(1).
+					Return(account2, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, db.ErrAccountNotActive)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
 		{
 			name: "FromAcountNotFound",
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"amount":          amountInput,
+				"currency":        money.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -130,8 +293,8 @@ func TestCreateTransfer(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"amount":          amountInput,
+				"currency":        money.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -156,8 +319,8 @@ func TestCreateTransfer(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        "CAD",
+				"amount":          amountInput,
+				"currency":        "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -175,13 +338,51 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, recorder.Code, http.StatusBadRequest)
 			},
 		},
+		{
+			name: "TooManyDecimals",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          "0.001",
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeAmount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          "-1.00",
+				"currency":        money.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, t *testing.T) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, t *testing.T) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidAccount",
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.VND,
+				"amount":          "10",
+				"currency":        money.VND,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -201,8 +402,8 @@ func TestCreateTransfer(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.VND,
+				"amount":          "10",
+				"currency":        money.VND,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authoriztionTypeBearer, user1.Username, time.Minute)
@@ -226,6 +427,7 @@ func TestCreateTransfer(t *testing.T) {
 			defer ctrl.Finish()
 
 			store := mockdb.NewMockStore(ctrl)
+			expectVerifiedUsers(store)
 
 			tc.buildStubs(store, t)
 
@@ -246,3 +448,600 @@ func TestCreateTransfer(t *testing.T) {
 	}
 
 }
+
+func TestGetTransferAPI(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = money.USD
+	account2.Currency = money.EUR
+
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+		ToAmount:      900,
+		ExchangeRate:  "0.9",
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Sender",
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().ListTransferReversals(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, "10.00", rsp["amount"])
+				require.Equal(t, "9.00", rsp["to_amount"])
+			},
+		},
+		{
+			name:     "Recipient",
+			username: user2.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().ListTransferReversals(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Banker",
+			username: util.RandomOwner(),
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(account1, nil)
+				store.EXPECT().ListTransferReversals(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Forbidden",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().ListTransferReversals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "PartiallyReversed",
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				reversed := transfer
+				reversed.ReversedAmount = 400
+				reversal := db.Transfer{
+					ID:            transfer.ID + 1,
+					FromAccountID: account2.ID,
+					ToAccountID:   account1.ID,
+					Amount:        360,
+					ToAmount:      400,
+					ExchangeRate:  "1.111111111111",
+					ReversalOf:    sql.NullInt64{Int64: transfer.ID, Valid: true},
+				}
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(reversed, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					ListTransferReversals(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: transfer.ID, Valid: true})).
+					Times(1).
+					Return([]db.Transfer{reversal}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, "partially_reversed", rsp["status"])
+				require.Equal(t, "4.00", rsp["reversed_amount"])
+				require.NotContains(t, rsp, "reversal_of")
+
+				reversals := rsp["reversals"].([]any)
+				require.Len(t, reversals, 1)
+				reversal := reversals[0].(map[string]any)
+				require.Equal(t, float64(transfer.ID), reversal["reversal_of"])
+				require.Equal(t, "3.60", reversal["amount"])
+				require.Equal(t, "4.00", reversal["to_amount"])
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.Transfer{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfers/%d", transfer.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReverseTransferAPI(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = money.USD
+	account2.Currency = money.EUR
+
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+		ToAmount:      900,
+		ExchangeRate:  "0.9",
+	}
+	result := db.ReverseTransferTxResult{
+		Original: db.Transfer{
+			ID:             transfer.ID,
+			FromAccountID:  account1.ID,
+			ToAccountID:    account2.ID,
+			Amount:         1000,
+			ToAmount:       900,
+			ExchangeRate:   "0.9",
+			ReversedAmount: 1000,
+		},
+		TransferTxResult: db.TransferTxResult{
+			Transfer: db.Transfer{
+				ID:            transfer.ID + 1,
+				FromAccountID: account2.ID,
+				ToAccountID:   account1.ID,
+				Amount:        900,
+				ToAmount:      1000,
+				ExchangeRate:  "1.111111111111",
+				ReversalOf:    sql.NullInt64{Int64: transfer.ID, Valid: true},
+			},
+			FromAccount: account2,
+			ToAccount:   account1,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Full",
+			username: user2.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{
+					TransferID:   transfer.ID,
+					ExchangeRate: "1.111111111111",
+				}
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, "reversed", rsp["original"]["status"])
+				require.Equal(t, "10.00", rsp["original"]["reversed_amount"])
+
+				reversal := rsp["reversal"]["transfer"].(map[string]any)
+				require.Equal(t, float64(transfer.ID), reversal["reversal_of"])
+				require.Equal(t, "9.00", reversal["amount"])
+				require.Equal(t, "10.00", reversal["to_amount"])
+			},
+		},
+		{
+			name:     "Partial",
+			username: user2.Username,
+			body:     gin.H{"amount": "4.00"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{
+					TransferID:   transfer.ID,
+					Amount:       400,
+					ExchangeRate: "1.111111111111",
+				}
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Admin",
+			username: util.RandomOwner(),
+			role:     db.UserRoleAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Sender",
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Banker",
+			username: util.RandomOwner(),
+			role:     db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NegativeAmount",
+			username: user2.Username,
+			body:     gin.H{"amount": "-1"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "OverReversal",
+			username: user2.Username,
+			body:     gin.H{"amount": "20"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, fmt.Errorf("%w: test", db.ErrOverReversal))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "InsufficientFunds",
+			username: user2.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			username: user2.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				err := json.NewEncoder(&body).Encode(tc.body)
+				require.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/transfers/%d/reverse", transfer.ID)
+			request, err := http.NewRequest(http.MethodPost, url, &body)
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferEmailNotVerified(t *testing.T) {
+	user, _ := createRandomUser(t)
+	user.IsEmailVerified = false
+	account1 := randomAccount(user.Username)
+	account1.Currency = money.USD
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account1.ID + 1,
+		"amount":          "10",
+		"currency":        money.USD,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Contains(t, recorder.Body.String(), errEmailNotVerified.Error())
+}
+
+func TestCreateTransferRequiresTOTP(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account1 := randomAccount(user.Username)
+	account1.Currency = money.USD
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	userTotp := db.UserTotp{
+		Username:    user.Username,
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		amount        string
+		code          func(t *testing.T) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "BelowThreshold",
+			amount: "999.99",
+			code:   func(t *testing.T) string { return "" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID+1)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// the request went past the two-factor check
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "CodeRequired",
+			amount: "1000",
+			code:   func(t *testing.T) string { return "" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userTotp, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), mfa.ErrCodeRequired.Error())
+			},
+		},
+		{
+			name:   "ReplayedCode",
+			amount: "1000",
+			code: func(t *testing.T) string {
+				code, err := mfa.GenerateCode(secret, time.Now())
+				require.NoError(t, err)
+				return code
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(userTotp, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(db.UserTotp{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), mfa.ErrInvalidCode.Error())
+			},
+		},
+		{
+			name:   "FreshCode",
+			amount: "1000",
+			code: func(t *testing.T) string {
+				code, err := mfa.GenerateCode(secret, time.Now())
+				require.NoError(t, err)
+				return code
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(userTotp, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(userTotp, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID+1)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			expectVerifiedUsers(store)
+			tc.buildStubs(store)
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+			server := NewTestServer(t, store)
+			thresholds, err := mfa.ParseTransferThresholds("USD:1000")
+			require.NoError(t, err)
+			server.mfa = mfa.NewService(store, "Simple Bank", thresholds)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account1.ID + 1,
+				"amount":          tc.amount,
+				"currency":        money.USD,
+				"totp_code":       tc.code(t),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferTOTPLockout(t *testing.T) {
+	user, _ := createRandomUser(t)
+	account1 := randomAccount(user.Username)
+	account1.Currency = money.USD
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	userTotp := db.UserTotp{
+		Username:    user.Username,
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).AnyTimes().Return(account1, nil)
+	expectVerifiedUsers(store)
+	store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(userTotp, nil)
+	store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	thresholds, err := mfa.ParseTransferThresholds("USD:1000")
+	require.NoError(t, err)
+	server.mfa = mfa.NewService(store, "Simple Bank", thresholds)
+
+	createTransfer := func(code string) *httptest.ResponseRecorder {
+		data, err := json.Marshal(gin.H{
+			"from_account_id": account1.ID,
+			"to_account_id":   account1.ID + 1,
+			"amount":          "1000",
+			"currency":        money.USD,
+			"totp_code":       code,
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	code, err := mfa.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	wrongCode := wrongTOTPCode(code)
+
+	for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+		recorder := createTransfer(wrongCode)
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+		require.Contains(t, recorder.Body.String(), mfa.ErrInvalidCode.Error())
+	}
+
+	// once locked out, no more codes are accepted, not even the right one
+	recorder := createTransfer(code)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	_, err = server.lockout.Reserve(context.Background(), user.Username, "")
+	require.ErrorIs(t, err, lockout.ErrLocked)
+}
+
+func wrongTOTPCode(code string) string {
+	digits := []byte(code)
+	for i := range digits {
+		digits[i] = '0' + (digits[i]-'0'+5)%10
+	}
+	return string(digits)
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getTxStats shows how often database transactions were retried since the server started.
+// Growing deadlock or exhausted counts point at transactions that lock rows in different orders.
+func (server *Server) getTxStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.store.TxStats())
+}
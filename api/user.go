@@ -1,12 +1,17 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/token"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/lib/pq"
 )
@@ -19,11 +24,13 @@ type createUserRequest struct {
 }
 
 type userResponse struct {
-	Username          string    `json:"username"`
-	FullName          string    `json:"full_name"`
-	Email             string    `json:"email"`
-	PasswordChangedAt time.Time `json:"password_changed_at"`
-	CreatedAt         time.Time `json:"created_at"`
+	Username          string      `json:"username"`
+	FullName          string      `json:"full_name"`
+	Email             string      `json:"email"`
+	IsEmailVerified   bool        `json:"is_email_verified"`
+	Role              db.UserRole `json:"role"`
+	PasswordChangedAt time.Time   `json:"password_changed_at"`
+	CreatedAt         time.Time   `json:"created_at"`
 }
 
 func NewUserResponse(user db.User) userResponse {
@@ -31,6 +38,8 @@ func NewUserResponse(user db.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		IsEmailVerified:   user.IsEmailVerified,
+		Role:              user.Role,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -49,14 +58,24 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
-		FullName:       req.FullName,
-		Email:          req.Email,
+	secretCode, err := util.RandomSecret(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			HashedPassword: hashedPassword,
+			FullName:       req.FullName,
+			Email:          req.Email,
+		},
+		SecretCode:           secretCode,
+		VerifyEmailExpiredAt: time.Now().Add(server.config.VerifyEmailDuration),
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	result, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
 
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -70,16 +89,37 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	res := NewUserResponse(user)
+	// the code is only mailed once it is committed, a retried transaction would mail it again
+	if err := server.sendVerifyEmail(ctx, result.User, result.VerifyEmail); err != nil {
+		err = fmt.Errorf("user %s was created but the verification email cannot be sent: %w", result.User.Username, err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := NewUserResponse(result.User)
 
 	ctx.JSON(http.StatusOK, res)
 }
 
+// sendVerifyEmail mails the link that verifies the email of a user
+func (server *Server) sendVerifyEmail(ctx context.Context, user db.User, verifyEmail db.VerifyEmail) error {
+	link := mail.VerifyEmailLink(server.config.PublicBaseURL, verifyEmail.ID, verifyEmail.SecretCode)
+	return server.mailer.Send(ctx, mail.NewVerifyEmailMessage(user.Email, user.FullName, link, verifyEmail.ExpiredAt))
+}
+
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// loginMFARequiredResponse is returned instead of loginUserResponse to users with two-factor
+// authentication. The MFA token is exchanged for a loginUserResponse at POST /users/login/mfa.
+type loginMFARequiredResponse struct {
+	MFARequired       bool      `json:"mfa_required"`
+	MFAToken          string    `json:"mfa_token"`
+	MFATokenExpiredAt time.Time `json:"mfa_token_expired_at"`
+}
+
 type loginUserResponse struct {
 	AccessToken           string       `json:"access_token"`
 	AccessTokenExpiredAt  time.Time    `json:"access_token_expired_at"`
@@ -95,10 +135,16 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	reservation, ok := server.reserveLogin(ctx, req.Username)
+	if !ok {
+		return
+	}
+
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			util.CheckPasswordOfUnknownUser(req.Password)
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
 			return
 		}
 
@@ -108,17 +154,51 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		return
+	}
+	if !server.refundLogin(ctx, reservation) {
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(req.Username, server.config.AccessTokenDuration)
+	mfaEnabled, err := server.mfa.Enabled(ctx, user.Username)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if mfaEnabled {
+		mfaToken, mfaPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeMFAPending, server.config.MFATokenDuration)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, loginMFARequiredResponse{
+			MFARequired:       true,
+			MFAToken:          mfaToken,
+			MFATokenExpiredAt: mfaPayload.ExpiredAt,
+		})
+		return
+	}
+
+	server.startSession(ctx, user)
+}
+
+// startSession completes a login: it clears the failed logins of the user, issues the
+// access and refresh tokens and records the session of the refresh token
+func (server *Server) startSession(ctx *gin.Context, user db.User) {
+	if err := server.lockout.Reset(ctx, user.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(req.Username, server.config.RefreshTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeRefresh, server.config.RefreshTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -132,6 +212,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		IsBlocked:    false,
 		ExpiredAt:    refreshPayload.ExpiredAt,
 		RefreshToken: refreshToken,
+		FamilyID:     refreshPayload.ID,
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -148,3 +229,184 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+type userURIRequest struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+func (server *Server) getUser(ctx *gin.Context) {
+	var req userURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(user))
+}
+
+type updateUserRoleRequest struct {
+	Role db.UserRole `json:"role" binding:"required,oneof=depositor banker admin"`
+}
+
+// updateUserRole lets admins promote or demote users. The new role is picked up by the
+// user's next login or access token renewal.
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	var uri userURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	if uri.Username == authPayload.Username {
+		err := errors.New("admins cannot change their own role")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(user))
+}
+
+type verifyEmailRequest struct {
+	ID   int64  `form:"id" binding:"required,min=1"`
+	Code string `form:"code" binding:"required"`
+}
+
+// verifyEmail is opened from the link sent to new users, so it needs no authentication
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
+		EmailID:    req.ID,
+		SecretCode: req.Code,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidVerifyEmail) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(result.User))
+}
+
+func (server *Server) getMe(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(user))
+}
+
+// updateMeRequest only changes the fields that are present
+type updateMeRequest struct {
+	FullName *string `json:"full_name" binding:"omitempty,min=1"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+}
+
+var errNothingToUpdate = errors.New("at least one of full_name and email is required")
+
+// updateMe changes the profile of the caller. A new email is unverified until the user opens
+// the link sent to it, and transfers are refused until then.
+func (server *Server) updateMe(ctx *gin.Context) {
+	var req updateMeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.FullName == nil && req.Email == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errNothingToUpdate))
+		return
+	}
+
+	authPayload := ctx.MustGet(authoriztionPayloadKey).(*token.Payload)
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: authPayload.Username,
+		},
+		VerifyEmailExpiredAt: time.Now().Add(server.config.VerifyEmailDuration),
+	}
+	if req.FullName != nil {
+		arg.FullName = sql.NullString{String: *req.FullName, Valid: true}
+	}
+	if req.Email != nil {
+		arg.Email = sql.NullString{String: *req.Email, Valid: true}
+
+		secretCode, err := util.RandomSecret(32)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.SecretCode = secretCode
+	}
+
+	result, err := server.store.UpdateUserTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.EmailChanged {
+		if err := server.sendVerifyEmail(ctx, result.User, result.VerifyEmail); err != nil {
+			err = fmt.Errorf("email was changed but the verification email cannot be sent: %w", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, NewUserResponse(result.User))
+}
@@ -2,17 +2,21 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
@@ -39,9 +43,9 @@ func TestCreateUser(t *testing.T) {
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(user, nil)
+					Return(db.CreateUserTxResult{User: user}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusOK)
@@ -58,7 +62,7 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -75,7 +79,7 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -92,7 +96,7 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -109,7 +113,7 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -126,7 +130,7 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -143,9 +147,9 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, sql.ErrConnDone)
+					Return(db.CreateUserTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusInternalServerError)
@@ -161,9 +165,9 @@ func TestCreateUser(t *testing.T) {
 			},
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, &pq.Error{Code: "23505"})
+					Return(db.CreateUserTxResult{}, &pq.Error{Code: "23505"})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusForbidden)
@@ -203,6 +207,122 @@ func TestCreateUser(t *testing.T) {
 
 }
 
+func TestUpdateUserRoleAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	admin := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          db.UserRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"role": db.UserRoleBanker},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserRoleParams{
+					Username: user.Username,
+					Role:     db.UserRoleBanker,
+				}
+				promoted := user
+				promoted.Role = db.UserRoleBanker
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(promoted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, db.UserRoleBanker, rsp.Role)
+			},
+		},
+		{
+			name:     "BankerForbidden",
+			username: admin,
+			role:     db.UserRoleBanker,
+			body:     gin.H{"role": db.UserRoleAdmin},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnRole",
+			username: user.Username,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"role": db.UserRoleDepositor},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidRole",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"role": "root"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UserNotFound",
+			username: admin,
+			role:     db.UserRoleAdmin,
+			body:     gin.H{"role": db.UserRoleBanker},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/users/%s/role", user.Username)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addRoleAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func createRandomUser(t *testing.T) (user db.User, password string) {
 	password = util.RandomPassword()
 	hashedPassword, err := util.HashedPassword(password)
@@ -214,10 +334,23 @@ func createRandomUser(t *testing.T) (user db.User, password string) {
 		HashedPassword: hashedPassword,
 		FullName:       util.RandomFullname(),
 		Email:          util.RandomEmail(),
+		Role:           db.UserRoleDepositor,
+		// most tests act as an established user, TestCreateUser covers new ones
+		IsEmailVerified: true,
 	}
 	return
 }
 
+// expectVerifiedUsers lets every user through requireVerifiedEmail
+func expectVerifiedUsers(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.User, error) {
+			return db.User{Username: username, Role: db.UserRoleDepositor, IsEmailVerified: true}, nil
+		})
+}
+
 func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
@@ -231,3 +364,375 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	require.Zero(t, gotUser.PasswordChangedAt)
 	require.Empty(t, gotUser.HashedPassword)
 }
+
+func TestCreateUserSendsVerifyEmail(t *testing.T) {
+	user, password := createRandomUser(t)
+	user.IsEmailVerified = false
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	verifyEmail := db.VerifyEmail{
+		ID:        util.RandomInt(1, 1000),
+		Username:  user.Username,
+		Email:     user.Email,
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+	store.EXPECT().
+		CreateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.NoError(t, util.CheckPassword(password, arg.HashedPassword))
+			require.GreaterOrEqual(t, len(arg.SecretCode), 32)
+			require.WithinDuration(t, time.Now().Add(time.Hour), arg.VerifyEmailExpiredAt, time.Second)
+
+			verifyEmail.SecretCode = arg.SecretCode
+			return db.CreateUserTxResult{User: user, VerifyEmail: verifyEmail}, nil
+		})
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"username":  user.Username,
+		"password":  password,
+		"full_name": user.FullName,
+		"email":     user.Email,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp userResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.False(t, rsp.IsEmailVerified)
+
+	messages := server.mailer.(*mail.MemoryMailer).Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, []string{user.Email}, messages[0].To)
+	require.Contains(t, messages[0].Body, mail.VerifyEmailLink("http://localhost:8080", verifyEmail.ID, verifyEmail.SecretCode))
+}
+
+func TestCreateUserFailureSendsNoEmail(t *testing.T) {
+	user, password := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.CreateUserTxResult{}, sql.ErrConnDone)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"username":  user.Username,
+		"password":  password,
+		"full_name": user.FullName,
+		"email":     user.Email,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	// the verification code of a user that was not committed is never mailed
+	require.Empty(t, server.mailer.(*mail.MemoryMailer).Messages())
+}
+
+func TestVerifyEmailAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	emailID := util.RandomInt(1, 1000)
+	code := util.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"id": {fmt.Sprint(emailID)}, "code": {code}},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.VerifyEmailTxParams{
+					EmailID:    emailID,
+					SecretCode: code,
+				}
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.VerifyEmailTxResult{User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, rsp.Username)
+				require.True(t, rsp.IsEmailVerified)
+			},
+		},
+		{
+			name:  "InvalidCode",
+			query: url.Values{"id": {fmt.Sprint(emailID)}, "code": {code}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, db.ErrInvalidVerifyEmail)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingCode",
+			query: url.Values{"id": {fmt.Sprint(emailID)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: url.Values{"id": {fmt.Sprint(emailID)}, "code": {code}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/users/verify-email?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetMeAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/me", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp userResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.Equal(t, NewUserResponse(user), rsp)
+}
+
+func TestUpdateMeAPI(t *testing.T) {
+	user, _ := createRandomUser(t)
+	newFullName := util.RandomFullname()
+	newEmail := util.RandomEmail()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message)
+	}{
+		{
+			name: "FullName",
+			body: gin.H{"full_name": newFullName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, sql.NullString{String: newFullName, Valid: true}, arg.FullName)
+						require.False(t, arg.Email.Valid)
+
+						updated := user
+						updated.FullName = newFullName
+						return db.UpdateUserTxResult{User: updated}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, newFullName, rsp.FullName)
+				require.Equal(t, user.Email, rsp.Email)
+				require.True(t, rsp.IsEmailVerified)
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name: "Email",
+			body: gin.H{"email": newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						require.False(t, arg.FullName.Valid)
+						require.Equal(t, sql.NullString{String: newEmail, Valid: true}, arg.Email)
+						require.GreaterOrEqual(t, len(arg.SecretCode), 32)
+
+						updated := user
+						updated.Email = newEmail
+						updated.IsEmailVerified = false
+						verifyEmail := db.VerifyEmail{
+							ID:         1,
+							Username:   user.Username,
+							Email:      newEmail,
+							SecretCode: arg.SecretCode,
+							ExpiredAt:  arg.VerifyEmailExpiredAt,
+						}
+						return db.UpdateUserTxResult{User: updated, EmailChanged: true, VerifyEmail: verifyEmail}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, newEmail, rsp.Email)
+				require.False(t, rsp.IsEmailVerified)
+
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{newEmail}, messages[0].To)
+			},
+		},
+		{
+			name: "NothingToUpdate",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyFullName",
+			body: gin.H{"full_name": ""},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"email": "invalid-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateEmail",
+			body: gin.H{"email": newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				// nothing is mailed for an email change that was not committed
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"full_name": newFullName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authoriztionTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.mailer.(*mail.MemoryMailer).Messages())
+		})
+	}
+}
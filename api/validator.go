@@ -2,12 +2,12 @@ package api
 
 import (
 	"github.com/go-playground/validator/v10"
-	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/lamdangtung/golang-sample-bank/money"
 )
 
 var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	if currency, ok := fieldLevel.Field().Interface().(string); ok {
-		if util.IsSupportedCurrency(currency) {
+		if money.IsSupported(currency) {
 			return true
 		}
 	}
@@ -0,0 +1,45 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+type testPosition struct {
+	ID int64 `json:"id"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec(util.RandomString(32))
+
+	cursor, err := codec.Encode("accounts:alice", testPosition{ID: 42})
+	require.NoError(t, err)
+
+	var position testPosition
+	err = codec.Decode("accounts:alice", cursor, &position)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), position.ID)
+
+	// a cursor is only valid for the list it was issued for
+	err = codec.Decode("accounts:bob", cursor, &position)
+	require.ErrorIs(t, err, ErrInvalid)
+
+	// nor with another key
+	err = NewCodec(util.RandomString(32)).Decode("accounts:alice", cursor, &position)
+	require.ErrorIs(t, err, ErrInvalid)
+
+	// changing the position breaks the signature
+	_, signature, _ := strings.Cut(cursor, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"id":1}`)) + "." + signature
+	err = codec.Decode("accounts:alice", forged, &position)
+	require.ErrorIs(t, err, ErrInvalid)
+
+	for _, malformed := range []string{"", "abc", "!!.!!", cursor + "."} {
+		err = codec.Decode("accounts:alice", malformed, &position)
+		require.ErrorIs(t, err, ErrInvalid, malformed)
+	}
+}
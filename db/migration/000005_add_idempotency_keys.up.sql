@@ -0,0 +1,14 @@
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response_status" int NOT NULL DEFAULT 0,
+  "response_body" jsonb NOT NULL DEFAULT '{}',
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
+
+COMMENT ON COLUMN "idempotency_keys"."response_status" IS '0 while the original request is still in flight';
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,26 @@
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "base_currency" varchar NOT NULL,
+  "quote_currency" varchar NOT NULL,
+  "rate" numeric NOT NULL,
+  "valid_from" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("rate" > 0),
+  CHECK ("base_currency" <> "quote_currency")
+);
+
+CREATE UNIQUE INDEX ON "exchange_rates" ("base_currency", "quote_currency", "valid_from");
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'quote amount received for one unit of base amount';
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive, in the currency of the from account';
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'must be positive, in the currency of the to account';
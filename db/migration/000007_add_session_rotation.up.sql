@@ -0,0 +1,17 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "parent_id" uuid REFERENCES "sessions" ("id");
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+CREATE INDEX ON "sessions" ("family_id");
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the session created at login, shared by all of its rotations';
+
+COMMENT ON COLUMN "sessions"."parent_id" IS 'session whose refresh token was exchanged for this one';
+
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'set once the refresh token has been exchanged, it must not be used again';
@@ -0,0 +1,20 @@
+-- take the adjustments back out of the customer balances and remove their entries, so that
+-- every balance still equals the sum of its entries once the adjustments are gone
+UPDATE "accounts" SET "balance" = "accounts"."balance" - "adjusted"."amount"
+FROM (
+  SELECT "account_id", SUM("amount") AS "amount" FROM "balance_adjustments" GROUP BY "account_id"
+) AS "adjusted"
+WHERE "accounts"."id" = "adjusted"."account_id";
+
+WITH "removed" AS (
+  DELETE FROM "balance_adjustments" RETURNING "entry_id"
+)
+DELETE FROM "entries" WHERE "id" IN (SELECT "entry_id" FROM "removed");
+
+DROP TABLE IF EXISTS "balance_adjustments";
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'simplebank_suspense');
+
+DELETE FROM "accounts" WHERE "owner" = 'simplebank_suspense';
+
+DELETE FROM "users" WHERE "username" = 'simplebank_suspense';
@@ -0,0 +1,30 @@
+-- the bank owns one suspense account per currency, which takes the other side of every adjustment.
+-- The username is not alphanumeric so it can never be registered or logged into.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('simplebank_suspense', '!', 'Simple Bank suspense', 'suspense@simplebank.invalid');
+
+CREATE TABLE "balance_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL REFERENCES "accounts" ("id"),
+  "suspense_account_id" bigint NOT NULL REFERENCES "accounts" ("id"),
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "operator" varchar NOT NULL REFERENCES "users" ("username"),
+  "entry_id" bigint NOT NULL REFERENCES "entries" ("id"),
+  "suspense_entry_id" bigint NOT NULL REFERENCES "entries" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("amount" <> 0),
+  CHECK ("reason" <> '')
+);
+
+CREATE INDEX ON "balance_adjustments" ("account_id");
+
+CREATE INDEX ON "balance_adjustments" ("operator");
+
+CREATE INDEX ON "balance_adjustments" ("entry_id");
+
+CREATE INDEX ON "balance_adjustments" ("suspense_entry_id");
+
+COMMENT ON COLUMN "balance_adjustments"."amount" IS 'credited to the account when positive, debited when negative';
+
+COMMENT ON COLUMN "balance_adjustments"."operator" IS 'admin who made the adjustment';
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS "account_status";
@@ -0,0 +1,9 @@
+CREATE TYPE "account_status" AS ENUM (
+  'active',
+  'frozen',
+  'closed'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "status" account_status NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'only active accounts can be debited or credited, closed is final';
@@ -0,0 +1,9 @@
+CREATE TYPE "user_role" AS ENUM (
+  'depositor',
+  'banker',
+  'admin'
+);
+
+ALTER TABLE "users" ADD COLUMN "role" user_role NOT NULL DEFAULT 'depositor';
+
+COMMENT ON COLUMN "users"."role" IS 'bankers can view every account, admins additionally manage users and adjustments';
@@ -0,0 +1,9 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that created the entry, entries written before this column existed are not linked';
@@ -0,0 +1 @@
+-- nothing to undo: the backfilled transfer_id values go away with the column in 000011 down
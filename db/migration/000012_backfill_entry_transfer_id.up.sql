@@ -0,0 +1,11 @@
+-- link the entries written before entries.transfer_id existed to their transfer. TransferTx
+-- writes the transfer and both entries in one transaction, so they share created_at (now()
+-- is the start of the transaction) and the entries carry -amount and to_amount.
+UPDATE "entries" SET "transfer_id" = "transfers"."id"
+FROM "transfers"
+WHERE "entries"."transfer_id" IS NULL
+  AND "entries"."created_at" = "transfers"."created_at"
+  AND (
+    ("entries"."account_id" = "transfers"."from_account_id" AND "entries"."amount" = -"transfers"."amount")
+    OR ("entries"."account_id" = "transfers"."to_account_id" AND "entries"."amount" = "transfers"."to_amount")
+  );
@@ -0,0 +1,11 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "transfers" ADD CHECK ("reversed_amount" >= 0 AND "reversed_amount" <= "amount");
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'transfer compensated by this one, reversals cannot be reversed themselves';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'part of amount already paid back by reversals, in the currency of the from account';
@@ -0,0 +1,18 @@
+ALTER TABLE "users" ADD COLUMN "is_email_verified" bool NOT NULL DEFAULT false;
+
+-- users registered before email verification existed, and the suspense user, are trusted as they are
+UPDATE "users" SET "is_email_verified" = true;
+
+CREATE TABLE "verify_emails" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users" ("username"),
+  "email" varchar NOT NULL,
+  "secret_code" varchar NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "verify_emails" ("username");
+
+COMMENT ON COLUMN "verify_emails"."email" IS 'address the code was sent to, it only verifies the user while this is still their email';
@@ -0,0 +1,10 @@
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users" ("username"),
+  "secret_code" varchar NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "password_resets" ("username");
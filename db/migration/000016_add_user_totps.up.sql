@@ -0,0 +1,21 @@
+CREATE TABLE "user_totps" (
+  "username" varchar PRIMARY KEY REFERENCES "users" ("username"),
+  "secret" varchar NOT NULL,
+  "confirmed_at" timestamptz,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "user_totps"."confirmed_at" IS 'two-factor authentication is enabled once the first code of the secret is confirmed';
+
+COMMENT ON COLUMN "user_totps"."last_used_step" IS 'time step of the last accepted code, codes of this step or an earlier one cannot be replayed';
+
+CREATE TABLE "totp_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users" ("username"),
+  "hashed_code" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "totp_recovery_codes" ("username");
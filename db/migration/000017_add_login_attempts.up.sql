@@ -0,0 +1,7 @@
+CREATE TABLE "login_attempts" (
+  "key" varchar PRIMARY KEY,
+  "failures" int NOT NULL DEFAULT 0,
+  "last_failed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "login_attempts"."key" IS 'user:<username> or ip:<client ip>';
@@ -0,0 +1,4 @@
+ALTER TABLE "accounts" ADD COLUMN "frozen_by" varchar REFERENCES "users" ("username");
+ALTER TABLE "accounts" ADD COLUMN "frozen_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."frozen_by" IS 'the user who froze the account, null unless it is frozen';
@@ -16,6 +16,13 @@ SELECT * FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE;
 
 SELECT * FROM accounts WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3 ;
 
+-- name: ListAccountsAfter :many
+
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
 
 -- name: AddAccountBalance :one
 
@@ -25,6 +32,33 @@ UPDATE accounts set balance = balance + sqlc.arg(amount) WHERE id = sqlc.arg(id)
 
 UPDATE accounts set balance = $2 WHERE id = $1 RETURNING *;
 
--- name: DeleteAccount :exec
+-- name: UpdateAccountOverdraftLimit :one
+
+UPDATE accounts set overdraft_limit = $2 WHERE id = $1 RETURNING *;
+
+-- name: CreateSuspenseAccount :exec
+
+INSERT INTO accounts (owner, balance, currency)
+VALUES (sqlc.arg(owner), 0, sqlc.arg(currency))
+ON CONFLICT (owner, currency) DO NOTHING;
+
+-- name: GetAccountByOwnerAndCurrency :one
+
+SELECT * FROM accounts WHERE owner = $1 AND currency = $2 LIMIT 1;
+
+-- name: UpdateAccountStatus :one
+
+UPDATE accounts set
+  status = sqlc.arg(status),
+  frozen_by = sqlc.narg(frozen_by),
+  frozen_at = CASE WHEN sqlc.narg(frozen_by)::varchar IS NULL THEN NULL ELSE now() END
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: CloseAccount :one
 
-DELETE FROM accounts WHERE id = $1;
\ No newline at end of file
+-- frozen accounts are only closed when close_frozen is set, for staff
+UPDATE accounts set status = 'closed'
+WHERE id = sqlc.arg(id) AND balance = 0
+  AND (status = 'active' OR (status = 'frozen' AND sqlc.arg(close_frozen)::bool))
+RETURNING *;
@@ -0,0 +1,18 @@
+-- name: CreateBalanceAdjustment :one
+
+INSERT INTO
+    balance_adjustments (account_id, suspense_account_id, amount, reason, operator, entry_id, suspense_entry_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetBalanceAdjustment :one
+
+SELECT * FROM balance_adjustments WHERE id = $1 LIMIT 1;
+
+-- name: ListBalanceAdjustments :many
+
+SELECT sqlc.embed(balance_adjustments), accounts.currency FROM balance_adjustments
+JOIN accounts ON accounts.id = balance_adjustments.account_id
+WHERE (sqlc.narg(account_id)::bigint IS NULL OR balance_adjustments.account_id = sqlc.narg(account_id))
+  AND (sqlc.narg(after_id)::bigint IS NULL OR balance_adjustments.id < sqlc.narg(after_id))
+ORDER BY balance_adjustments.id DESC
+LIMIT sqlc.arg('limit');
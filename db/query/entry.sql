@@ -1,8 +1,8 @@
 -- name: CreateEntry :one
 
 INSERT INTO
-    entries (account_id , amount)
-VALUES ($1, $2) RETURNING *;
+    entries (account_id , amount, transfer_id)
+VALUES ($1, $2, $3) RETURNING *;
 
 -- name: GetEntry :one
 
@@ -22,4 +22,48 @@ UPDATE entries set amount = $2 WHERE id = $1 RETURNING *;
 
 -- name: DeleteEntry :exec
 
-DELETE FROM entries WHERE id = $1;
\ No newline at end of file
+DELETE FROM entries WHERE id = $1;
+
+-- name: SearchEntries :many
+
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(min_amount)::bigint IS NULL OR abs(amount) >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR abs(amount) <= sqlc.narg(max_amount))
+  AND (sqlc.narg(after_id)::bigint IS NULL OR CASE
+    WHEN sqlc.arg(order_by)::text = 'amount' AND NOT sqlc.arg(descending)::bool
+      THEN (abs(amount), id) > (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id))
+    WHEN sqlc.arg(order_by)::text = 'amount'
+      THEN (abs(amount), id) < (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id))
+    WHEN NOT sqlc.arg(descending)::bool THEN id > sqlc.narg(after_id)
+    ELSE id < sqlc.narg(after_id)
+  END)
+ORDER BY
+  CASE WHEN sqlc.arg(order_by)::text = 'amount' AND NOT sqlc.arg(descending)::bool THEN abs(amount) END ASC,
+  CASE WHEN sqlc.arg(order_by)::text = 'amount' AND sqlc.arg(descending)::bool THEN abs(amount) END DESC,
+  CASE WHEN NOT sqlc.arg(descending)::bool THEN id END ASC,
+  CASE WHEN sqlc.arg(descending)::bool THEN id END DESC
+LIMIT sqlc.arg('limit');
+
+
+-- name: SumEntriesSince :one
+
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: ListStatementEntries :many
+
+SELECT sqlc.embed(entries),
+  transfers.from_account_id AS transfer_from_account_id,
+  transfers.to_account_id AS transfer_to_account_id,
+  balance_adjustments.reason AS adjustment_reason
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN balance_adjustments ON balance_adjustments.entry_id = entries.id
+  OR balance_adjustments.suspense_entry_id = entries.id
+WHERE entries.account_id = sqlc.arg(account_id)
+  AND entries.created_at >= sqlc.arg(from_time)
+  AND entries.created_at < sqlc.arg(to_time)
+ORDER BY entries.id;
@@ -0,0 +1,20 @@
+-- name: CreateExchangeRate :one
+
+INSERT INTO
+    exchange_rates (base_currency, quote_currency, rate, valid_from)
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: GetExchangeRate :one
+
+SELECT * FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2 AND valid_from <= sqlc.arg(at)
+ORDER BY valid_from DESC
+LIMIT 1;
+
+-- name: ListExchangeRates :many
+
+SELECT * FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2
+ORDER BY valid_from DESC
+LIMIT $3
+OFFSET $4;
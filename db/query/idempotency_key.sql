@@ -0,0 +1,35 @@
+-- name: CreateIdempotencyKey :one
+
+INSERT INTO
+    idempotency_keys (username, key, request_hash, expired_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (username, key) DO UPDATE
+SET
+    request_hash = EXCLUDED.request_hash,
+    response_status = 0,
+    response_body = '{}',
+    expired_at = EXCLUDED.expired_at,
+    created_at = now()
+WHERE idempotency_keys.expired_at < now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+
+SELECT * FROM idempotency_keys WHERE username = $1 AND key = $2 LIMIT 1;
+
+-- name: UpdateIdempotencyKeyResponse :one
+
+UPDATE idempotency_keys
+SET
+    response_status = sqlc.arg(response_status),
+    response_body = sqlc.arg(response_body)
+WHERE username = sqlc.arg(username) AND key = sqlc.arg(key)
+RETURNING *;
+
+-- name: DeleteIdempotencyKey :exec
+
+DELETE FROM idempotency_keys WHERE username = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+
+DELETE FROM idempotency_keys WHERE expired_at < now();
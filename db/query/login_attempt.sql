@@ -0,0 +1,37 @@
+-- name: RecordLoginFailure :one
+
+-- counts a failed login, failures before forget_before are dropped first. previous_failed_at
+-- is the failure before this one, or failed_at for new keys, see RefundLoginFailure
+WITH previous AS (
+  SELECT last_failed_at FROM login_attempts WHERE key = sqlc.arg(key) FOR UPDATE
+)
+INSERT INTO login_attempts (key, failures, last_failed_at)
+VALUES (sqlc.arg(key), 1, sqlc.arg(failed_at))
+ON CONFLICT (key) DO UPDATE
+  SET failures = CASE
+        WHEN login_attempts.last_failed_at < sqlc.arg(forget_before) THEN 1
+        ELSE login_attempts.failures + 1
+      END,
+      last_failed_at = EXCLUDED.last_failed_at
+RETURNING login_attempts.key, login_attempts.failures, login_attempts.last_failed_at,
+  COALESCE((SELECT last_failed_at FROM previous), sqlc.arg(failed_at))::timestamptz AS previous_failed_at;
+
+-- name: RefundLoginFailure :exec
+
+-- takes back a failure counted ahead of a login that turned out right. last_failed_at goes back
+-- to previous_failed_at unless another failure was counted after failed_at.
+UPDATE login_attempts SET
+  failures = failures - 1,
+  last_failed_at = CASE
+    WHEN last_failed_at = sqlc.arg(failed_at) THEN sqlc.arg(previous_failed_at)
+    ELSE last_failed_at
+  END
+WHERE key = sqlc.arg(key) AND failures > 0;
+
+-- name: GetLoginAttempt :one
+
+SELECT * FROM login_attempts WHERE key = $1 LIMIT 1;
+
+-- name: DeleteLoginAttempt :exec
+
+DELETE FROM login_attempts WHERE key = $1;
@@ -0,0 +1,20 @@
+-- name: CreatePasswordReset :one
+
+INSERT INTO
+    password_resets (username, secret_code, expired_at)
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: UsePasswordReset :one
+
+-- marks an unused and unexpired code as used, no row means the code is not valid
+UPDATE password_resets SET is_used = TRUE
+WHERE id = sqlc.arg(id)
+  AND secret_code = sqlc.arg(secret_code)
+  AND is_used = FALSE
+  AND expired_at > now()
+RETURNING *;
+
+-- name: ExpireUserPasswordResets :execrows
+
+UPDATE password_resets SET is_used = TRUE
+WHERE username = $1 AND is_used = FALSE;
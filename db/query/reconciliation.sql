@@ -0,0 +1,55 @@
+-- name: CountLedger :one
+
+SELECT
+  (SELECT count(*) FROM accounts) AS accounts,
+  (SELECT count(*) FROM entries) AS entries,
+  (SELECT count(*) FROM transfers) AS transfers;
+
+-- name: ListBalanceDrifts :many
+
+-- accounts whose balance is not the sum of their entries
+SELECT accounts.id, accounts.owner, accounts.currency, accounts.balance,
+  COALESCE(SUM(entries.amount), 0)::bigint AS entries_total
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+GROUP BY accounts.id
+HAVING accounts.balance <> COALESCE(SUM(entries.amount), 0)
+ORDER BY accounts.id;
+
+-- name: ListOrphanedEntries :many
+
+-- entries written neither by a transfer nor by a balance adjustment
+SELECT entries.*, accounts.currency FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE entries.transfer_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM balance_adjustments
+    WHERE balance_adjustments.entry_id = entries.id OR balance_adjustments.suspense_entry_id = entries.id
+  )
+ORDER BY entries.id;
+
+-- name: ListUnbalancedTransfers :many
+
+-- transfers without exactly one debit of amount on the from account
+-- and one credit of to_amount on the to account
+SELECT sqlc.embed(transfers), from_account.currency AS from_currency, to_account.currency AS to_currency,
+  count(entries.id) AS entry_count,
+  count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.from_account_id AND entries.amount = -transfers.amount
+  ) AS debit_count,
+  count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.to_account_id AND entries.amount = transfers.to_amount
+  ) AS credit_count
+FROM transfers
+JOIN accounts from_account ON from_account.id = transfers.from_account_id
+JOIN accounts to_account ON to_account.id = transfers.to_account_id
+LEFT JOIN entries ON entries.transfer_id = transfers.id
+GROUP BY transfers.id, from_account.currency, to_account.currency
+HAVING count(entries.id) <> 2
+  OR count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.from_account_id AND entries.amount = -transfers.amount
+  ) <> 1
+  OR count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.to_account_id AND entries.amount = transfers.to_amount
+  ) <> 1
+ORDER BY transfers.id;
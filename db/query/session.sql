@@ -1,9 +1,40 @@
 -- name: CreateSession :one
 
 INSERT INTO
-    sessions (id,username,refresh_token,user_agent,client_ip,is_blocked,expired_at)
-VALUES ($1, $2, $3, $4,$5,$6,$7) RETURNING *;
+    sessions (id,username,refresh_token,user_agent,client_ip,is_blocked,expired_at,family_id,parent_id)
+VALUES ($1, $2, $3, $4,$5,$6,$7,$8,$9) RETURNING *;
 
 -- name: GetSession :one
 
 SELECT * FROM sessions WHERE id = $1 LIMIT 1;
+
+-- name: ListActiveSessions :many
+
+SELECT * FROM sessions
+WHERE username = sqlc.arg(username) AND is_blocked = false AND rotated_at IS NULL AND expired_at > now()
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at), sqlc.narg(after_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: BlockSession :one
+
+UPDATE sessions SET is_blocked = true
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: BlockUserSessions :execrows
+
+UPDATE sessions SET is_blocked = true
+WHERE username = $1 AND is_blocked = false;
+
+-- name: RotateSession :one
+
+UPDATE sessions SET rotated_at = now()
+WHERE id = $1 AND rotated_at IS NULL
+RETURNING *;
+
+-- name: BlockSessionFamily :execrows
+
+UPDATE sessions SET is_blocked = true
+WHERE family_id = $1 AND is_blocked = false;
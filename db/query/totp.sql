@@ -0,0 +1,43 @@
+-- name: UpsertPendingUserTotp :one
+
+-- starts or restarts an enrollment, no row means two-factor authentication is already enabled
+INSERT INTO user_totps (username, secret) VALUES ($1, $2)
+ON CONFLICT (username) DO UPDATE
+  SET secret = EXCLUDED.secret, last_used_step = 0, created_at = now()
+  WHERE user_totps.confirmed_at IS NULL
+RETURNING *;
+
+-- name: GetUserTotp :one
+
+SELECT * FROM user_totps WHERE username = $1 LIMIT 1;
+
+-- name: ConfirmUserTotp :one
+
+UPDATE user_totps SET confirmed_at = now(), last_used_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username) AND confirmed_at IS NULL
+RETURNING *;
+
+-- name: UseUserTotpStep :one
+
+-- accepts a code once, no row means a code of this step or a later one was already used
+UPDATE user_totps SET last_used_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username) AND confirmed_at IS NOT NULL AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: DeleteUserTotp :exec
+
+DELETE FROM user_totps WHERE username = $1;
+
+-- name: CreateTotpRecoveryCode :one
+
+INSERT INTO totp_recovery_codes (username, hashed_code) VALUES ($1, $2) RETURNING *;
+
+-- name: UseTotpRecoveryCode :one
+
+UPDATE totp_recovery_codes SET used_at = now()
+WHERE username = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteTotpRecoveryCodes :exec
+
+DELETE FROM totp_recovery_codes WHERE username = $1;
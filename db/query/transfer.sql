@@ -1,13 +1,26 @@
 -- name: CreateTransfer :one
 
 INSERT INTO
-    transfers (from_account_id , to_account_id , amount)
-VALUES ($1, $2, $3) RETURNING *;
+    transfers (from_account_id , to_account_id , amount, to_amount, exchange_rate, reversal_of)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
 
 -- name: GetTransfer :one
 
 SELECT * FROM transfers  WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+
+SELECT * FROM transfers WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE;
+
+-- name: AddTransferReversedAmount :one
+
+UPDATE transfers SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id) RETURNING *;
+
+-- name: ListTransferReversals :many
+
+SELECT * FROM transfers WHERE reversal_of = $1 ORDER BY id;
+
 -- name: ListTransfers :many
 
 SELECT * FROM transfers 
@@ -22,4 +35,42 @@ UPDATE transfers  set amount = $2 WHERE id = $1 RETURNING *;
 
 -- name: DeleteTransfer :exec
 
-DELETE FROM transfers  WHERE id = $1;
\ No newline at end of file
+DELETE FROM transfers  WHERE id = $1;
+
+-- name: SearchTransfers :many
+
+-- amounts are compared in the currency of the given account:
+-- what it sent for outgoing transfers, what it received for incoming ones
+SELECT sqlc.embed(transfers), from_account.currency AS from_currency, to_account.currency AS to_currency
+FROM transfers
+JOIN accounts from_account ON from_account.id = transfers.from_account_id
+JOIN accounts to_account ON to_account.id = transfers.to_account_id
+WHERE (transfers.from_account_id = sqlc.arg(account_id) OR transfers.to_account_id = sqlc.arg(account_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR transfers.created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR transfers.created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(min_amount)::bigint IS NULL OR CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+  END >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+  END <= sqlc.narg(max_amount))
+  AND (sqlc.narg(after_id)::bigint IS NULL OR CASE
+    WHEN sqlc.arg(order_by)::text = 'amount' AND NOT sqlc.arg(descending)::bool THEN (CASE
+      WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+    END, transfers.id) > (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id))
+    WHEN sqlc.arg(order_by)::text = 'amount' THEN (CASE
+      WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+    END, transfers.id) < (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id))
+    WHEN NOT sqlc.arg(descending)::bool THEN transfers.id > sqlc.narg(after_id)
+    ELSE transfers.id < sqlc.narg(after_id)
+  END)
+ORDER BY
+  CASE WHEN sqlc.arg(order_by)::text = 'amount' AND NOT sqlc.arg(descending)::bool THEN CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+  END END ASC,
+  CASE WHEN sqlc.arg(order_by)::text = 'amount' AND sqlc.arg(descending)::bool THEN CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.amount ELSE transfers.to_amount
+  END END DESC,
+  CASE WHEN NOT sqlc.arg(descending)::bool THEN transfers.id END ASC,
+  CASE WHEN sqlc.arg(descending)::bool THEN transfers.id END DESC
+LIMIT sqlc.arg('limit');
@@ -6,4 +6,39 @@ VALUES ($1, $2, $3, $4) RETURNING *;
 
 -- name: GetUser :one
 
-SELECT * FROM users WHERE username = $1 LIMIT 1;
\ No newline at end of file
+SELECT * FROM users WHERE username = $1 LIMIT 1;
+
+-- name: UpdateUserRole :one
+
+UPDATE users SET role = $2 WHERE username = $1 RETURNING *;
+
+-- name: VerifyUserEmail :one
+
+UPDATE users SET is_email_verified = TRUE
+WHERE username = $1 AND email = $2 RETURNING *;
+
+-- name: GetUserByEmail :one
+
+SELECT * FROM users WHERE email = $1 LIMIT 1;
+
+-- name: GetUserPasswordChangedAt :one
+
+SELECT password_changed_at FROM users WHERE username = $1 LIMIT 1;
+
+-- name: UpdateUserPassword :one
+
+UPDATE users SET hashed_password = $2, password_changed_at = $3
+WHERE username = $1 RETURNING *;
+
+-- name: UpdateUser :one
+
+-- only the given fields change, a new email is not verified anymore
+UPDATE users SET
+  full_name = COALESCE(sqlc.narg(full_name), full_name),
+  email = COALESCE(sqlc.narg(email), email),
+  is_email_verified = CASE
+    WHEN sqlc.narg(email)::varchar IS NULL OR sqlc.narg(email) = email THEN is_email_verified
+    ELSE false
+  END
+WHERE username = sqlc.arg(username)
+RETURNING *;
@@ -7,11 +7,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 
-UPDATE accounts set balance = balance + $1 WHERE id = $2 RETURNING id, owner, balance, currency, created_at
+UPDATE accounts set balance = balance + $1 WHERE id = $2 RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
 `
 
 type AddAccountBalanceParams struct {
@@ -28,6 +29,41 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
+const closeAccount = `-- name: CloseAccount :one
+
+UPDATE accounts set status = 'closed'
+WHERE id = $1 AND balance = 0
+  AND (status = 'active' OR (status = 'frozen' AND $2::bool))
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
+`
+
+type CloseAccountParams struct {
+	ID          int64 `json:"id"`
+	CloseFrozen bool  `json:"close_frozen"`
+}
+
+// frozen accounts are only closed when close_frozen is set, for staff
+func (q *Queries) CloseAccount(ctx context.Context, arg CloseAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, closeAccount, arg.ID, arg.CloseFrozen)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
 	)
 	return i, err
 }
@@ -36,7 +72,7 @@ const createAccount = `-- name: CreateAccount :one
 
 INSERT INTO
     accounts (owner, balance, currency)
-VALUES ($1, $2, $3) RETURNING id, owner, balance, currency, created_at
+VALUES ($1, $2, $3) RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
 `
 
 type CreateAccountParams struct {
@@ -54,23 +90,34 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
+const createSuspenseAccount = `-- name: CreateSuspenseAccount :exec
 
-DELETE FROM accounts WHERE id = $1
+INSERT INTO accounts (owner, balance, currency)
+VALUES ($1, 0, $2)
+ON CONFLICT (owner, currency) DO NOTHING
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
+type CreateSuspenseAccountParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) CreateSuspenseAccount(ctx context.Context, arg CreateSuspenseAccountParams) error {
+	_, err := q.db.ExecContext(ctx, createSuspenseAccount, arg.Owner, arg.Currency)
 	return err
 }
 
 const getAccount = `-- name: GetAccount :one
 
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE id = $1 LIMIT 1
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at FROM accounts WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
@@ -82,13 +129,44 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
+const getAccountByOwnerAndCurrency = `-- name: GetAccountByOwnerAndCurrency :one
+
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at FROM accounts WHERE owner = $1 AND currency = $2 LIMIT 1
+`
+
+type GetAccountByOwnerAndCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetAccountByOwnerAndCurrency(ctx context.Context, arg GetAccountByOwnerAndCurrencyParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwnerAndCurrency, arg.Owner, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
 
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
@@ -100,13 +178,17 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
 
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at FROM accounts WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3
 `
 
 type ListAccountsParams struct {
@@ -130,6 +212,57 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.FrozenBy,
+			&i.FrozenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsAfter = `-- name: ListAccountsAfter :many
+
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at FROM accounts
+WHERE owner = $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListAccountsAfterParams struct {
+	Owner   string `json:"owner"`
+	AfterID int64  `json:"after_id"`
+	Limit   int32  `json:"limit"`
+}
+
+func (q *Queries) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsAfter, arg.Owner, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.FrozenBy,
+			&i.FrozenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -146,7 +279,7 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 
 const updateAccount = `-- name: UpdateAccount :one
 
-UPDATE accounts set balance = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at
+UPDATE accounts set balance = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
 `
 
 type UpdateAccountParams struct {
@@ -163,6 +296,76 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+
+UPDATE accounts set overdraft_limit = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64 `json:"id"`
+	OverdraftLimit int64 `json:"overdraft_limit"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+
+UPDATE accounts set
+  status = $1,
+  frozen_by = $2,
+  frozen_at = CASE WHEN $2::varchar IS NULL THEN NULL ELSE now() END
+WHERE id = $3 AND status = $4
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, frozen_by, frozen_at
+`
+
+type UpdateAccountStatusParams struct {
+	Status     AccountStatus  `json:"status"`
+	FrozenBy   sql.NullString `json:"frozen_by"`
+	ID         int64          `json:"id"`
+	FromStatus AccountStatus  `json:"from_status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus,
+		arg.Status,
+		arg.FrozenBy,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.FrozenBy,
+		&i.FrozenAt,
 	)
 	return i, err
 }
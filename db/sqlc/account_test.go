@@ -6,15 +6,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lamdangtung/golang-sample-bank/money"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func CreateRandomAccount(t *testing.T) Account {
+	return createRandomAccountWithBalance(t, util.RandomMoney())
+}
+
+func createRandomAccountWithBalance(t *testing.T, balance int64) Account {
 	user := CreateRandomUser(t)
 	arg := CreateAccountParams{
 		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
+		Balance:  balance,
 		Currency: util.RandomCurrency(),
 	}
 
@@ -70,20 +75,6 @@ func TestUpdateAccount(t *testing.T) {
 
 }
 
-func TestDeleteAccount(t *testing.T) {
-	account1 := CreateRandomAccount(t)
-
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
-
-	require.NoError(t, err)
-
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
-
-	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account2)
-}
-
 func TestListAccounts(t *testing.T) {
 	var lastAccount Account
 	for i := 0; i < 10; i++ {
@@ -107,3 +98,98 @@ func TestListAccounts(t *testing.T) {
 	}
 
 }
+
+func TestListAccountsAfter(t *testing.T) {
+	user := CreateRandomUser(t)
+	var accounts []Account
+	for _, currency := range []string{money.EUR, money.USD, money.VND} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	page, err := testQueries.ListAccountsAfter(context.Background(), ListAccountsAfterParams{
+		Owner: user.Username,
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, accounts[0].ID, page[0].ID)
+	require.Equal(t, accounts[1].ID, page[1].ID)
+
+	page, err = testQueries.ListAccountsAfter(context.Background(), ListAccountsAfterParams{
+		Owner:   user.Username,
+		AfterID: page[1].ID,
+		Limit:   2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, accounts[2].ID, page[0].ID)
+}
+
+func TestUpdateAccountStatus(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+	require.Equal(t, AccountStatusActive, account1.Status)
+
+	arg := UpdateAccountStatusParams{
+		ID:         account1.ID,
+		FromStatus: AccountStatusActive,
+		Status:     AccountStatusFrozen,
+		FrozenBy:   sql.NullString{String: account1.Owner, Valid: true},
+	}
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+	require.Equal(t, arg.FrozenBy, account2.FrozenBy)
+	require.True(t, account2.FrozenAt.Valid)
+	require.WithinDuration(t, time.Now(), account2.FrozenAt.Time, time.Second)
+
+	// the transition only applies from the expected status
+	_, err = testQueries.UpdateAccountStatus(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	// unfreezing clears who froze the account
+	account3, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account1.ID,
+		FromStatus: AccountStatusFrozen,
+		Status:     AccountStatusActive,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, account3.Status)
+	require.False(t, account3.FrozenBy.Valid)
+	require.False(t, account3.FrozenAt.Valid)
+}
+
+func TestCloseAccount(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+
+	_, err := testQueries.CloseAccount(context.Background(), CloseAccountParams{ID: account1.ID})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	account2 := createRandomAccountWithBalance(t, 0)
+	account3, err := testQueries.CloseAccount(context.Background(), CloseAccountParams{ID: account2.ID})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account3.Status)
+
+	_, err = testQueries.CloseAccount(context.Background(), CloseAccountParams{ID: account2.ID, CloseFrozen: true})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	// frozen accounts are only closed for staff
+	account4 := createRandomAccountWithBalance(t, 0)
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account4.ID,
+		FromStatus: AccountStatusActive,
+		Status:     AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CloseAccount(context.Background(), CloseAccountParams{ID: account4.ID})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	account5, err := testQueries.CloseAccount(context.Background(), CloseAccountParams{ID: account4.ID, CloseFrozen: true})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account5.Status)
+}
@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: balance_adjustment.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createBalanceAdjustment = `-- name: CreateBalanceAdjustment :one
+
+INSERT INTO
+    balance_adjustments (account_id, suspense_account_id, amount, reason, operator, entry_id, suspense_entry_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, account_id, suspense_account_id, amount, reason, operator, entry_id, suspense_entry_id, created_at
+`
+
+type CreateBalanceAdjustmentParams struct {
+	AccountID         int64  `json:"account_id"`
+	SuspenseAccountID int64  `json:"suspense_account_id"`
+	Amount            int64  `json:"amount"`
+	Reason            string `json:"reason"`
+	Operator          string `json:"operator"`
+	EntryID           int64  `json:"entry_id"`
+	SuspenseEntryID   int64  `json:"suspense_entry_id"`
+}
+
+func (q *Queries) CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error) {
+	row := q.db.QueryRowContext(ctx, createBalanceAdjustment,
+		arg.AccountID,
+		arg.SuspenseAccountID,
+		arg.Amount,
+		arg.Reason,
+		arg.Operator,
+		arg.EntryID,
+		arg.SuspenseEntryID,
+	)
+	var i BalanceAdjustment
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.SuspenseAccountID,
+		&i.Amount,
+		&i.Reason,
+		&i.Operator,
+		&i.EntryID,
+		&i.SuspenseEntryID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getBalanceAdjustment = `-- name: GetBalanceAdjustment :one
+
+SELECT id, account_id, suspense_account_id, amount, reason, operator, entry_id, suspense_entry_id, created_at FROM balance_adjustments WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetBalanceAdjustment(ctx context.Context, id int64) (BalanceAdjustment, error) {
+	row := q.db.QueryRowContext(ctx, getBalanceAdjustment, id)
+	var i BalanceAdjustment
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.SuspenseAccountID,
+		&i.Amount,
+		&i.Reason,
+		&i.Operator,
+		&i.EntryID,
+		&i.SuspenseEntryID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listBalanceAdjustments = `-- name: ListBalanceAdjustments :many
+
+SELECT balance_adjustments.id, balance_adjustments.account_id, balance_adjustments.suspense_account_id, balance_adjustments.amount, balance_adjustments.reason, balance_adjustments.operator, balance_adjustments.entry_id, balance_adjustments.suspense_entry_id, balance_adjustments.created_at, accounts.currency FROM balance_adjustments
+JOIN accounts ON accounts.id = balance_adjustments.account_id
+WHERE ($1::bigint IS NULL OR balance_adjustments.account_id = $1)
+  AND ($2::bigint IS NULL OR balance_adjustments.id < $2)
+ORDER BY balance_adjustments.id DESC
+LIMIT $3
+`
+
+type ListBalanceAdjustmentsParams struct {
+	AccountID sql.NullInt64 `json:"account_id"`
+	AfterID   sql.NullInt64 `json:"after_id"`
+	Limit     int32         `json:"limit"`
+}
+
+type ListBalanceAdjustmentsRow struct {
+	BalanceAdjustment BalanceAdjustment `json:"balance_adjustment"`
+	Currency          string            `json:"currency"`
+}
+
+func (q *Queries) ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]ListBalanceAdjustmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBalanceAdjustments, arg.AccountID, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBalanceAdjustmentsRow{}
+	for rows.Next() {
+		var i ListBalanceAdjustmentsRow
+		if err := rows.Scan(
+			&i.BalanceAdjustment.ID,
+			&i.BalanceAdjustment.AccountID,
+			&i.BalanceAdjustment.SuspenseAccountID,
+			&i.BalanceAdjustment.Amount,
+			&i.BalanceAdjustment.Reason,
+			&i.BalanceAdjustment.Operator,
+			&i.BalanceAdjustment.EntryID,
+			&i.BalanceAdjustment.SuspenseEntryID,
+			&i.BalanceAdjustment.CreatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,28 +7,32 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 
 INSERT INTO
-    entries (account_id , amount)
-VALUES ($1, $2) RETURNING id, account_id, amount, created_at
+    entries (account_id , amount, transfer_id)
+VALUES ($1, $2, $3) RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
@@ -45,7 +49,7 @@ func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
 
 const getEntry = `-- name: GetEntry :one
 
-SELECT id, account_id, amount, created_at FROM entries WHERE id = $1 LIMIT 1
+SELECT id, account_id, amount, created_at, transfer_id FROM entries WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
@@ -56,13 +60,14 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
 
-SELECT id, account_id, amount, created_at FROM entries 
+SELECT id, account_id, amount, created_at, transfer_id FROM entries 
 WHERE account_id = $1
 ORDER BY id 
 LIMIT $2 
@@ -89,6 +94,145 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+
+SELECT entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id,
+  transfers.from_account_id AS transfer_from_account_id,
+  transfers.to_account_id AS transfer_to_account_id,
+  balance_adjustments.reason AS adjustment_reason
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN balance_adjustments ON balance_adjustments.entry_id = entries.id
+  OR balance_adjustments.suspense_entry_id = entries.id
+WHERE entries.account_id = $1
+  AND entries.created_at >= $2
+  AND entries.created_at < $3
+ORDER BY entries.id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type ListStatementEntriesRow struct {
+	Entry                 Entry          `json:"entry"`
+	TransferFromAccountID sql.NullInt64  `json:"transfer_from_account_id"`
+	TransferToAccountID   sql.NullInt64  `json:"transfer_to_account_id"`
+	AdjustmentReason      sql.NullString `json:"adjustment_reason"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.Entry.ID,
+			&i.Entry.AccountID,
+			&i.Entry.Amount,
+			&i.Entry.CreatedAt,
+			&i.Entry.TransferID,
+			&i.TransferFromAccountID,
+			&i.TransferToAccountID,
+			&i.AdjustmentReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchEntries = `-- name: SearchEntries :many
+
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE account_id = $1
+  AND ($2::timestamptz IS NULL OR created_at >= $2)
+  AND ($3::timestamptz IS NULL OR created_at < $3)
+  AND ($4::bigint IS NULL OR abs(amount) >= $4)
+  AND ($5::bigint IS NULL OR abs(amount) <= $5)
+  AND ($6::bigint IS NULL OR CASE
+    WHEN $7::text = 'amount' AND NOT $8::bool
+      THEN (abs(amount), id) > ($9::bigint, $6)
+    WHEN $7::text = 'amount'
+      THEN (abs(amount), id) < ($9::bigint, $6)
+    WHEN NOT $8::bool THEN id > $6
+    ELSE id < $6
+  END)
+ORDER BY
+  CASE WHEN $7::text = 'amount' AND NOT $8::bool THEN abs(amount) END ASC,
+  CASE WHEN $7::text = 'amount' AND $8::bool THEN abs(amount) END DESC,
+  CASE WHEN NOT $8::bool THEN id END ASC,
+  CASE WHEN $8::bool THEN id END DESC
+LIMIT $10
+`
+
+type SearchEntriesParams struct {
+	AccountID   int64         `json:"account_id"`
+	FromTime    sql.NullTime  `json:"from_time"`
+	ToTime      sql.NullTime  `json:"to_time"`
+	MinAmount   sql.NullInt64 `json:"min_amount"`
+	MaxAmount   sql.NullInt64 `json:"max_amount"`
+	AfterID     sql.NullInt64 `json:"after_id"`
+	OrderBy     string        `json:"order_by"`
+	Descending  bool          `json:"descending"`
+	AfterAmount int64         `json:"after_amount"`
+	Limit       int32         `json:"limit"`
+}
+
+func (q *Queries) SearchEntries(ctx context.Context, arg SearchEntriesParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, searchEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.AfterID,
+		arg.OrderBy,
+		arg.Descending,
+		arg.AfterAmount,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -103,9 +247,27 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	return items, nil
 }
 
+const sumEntriesSince = `-- name: SumEntriesSince :one
+
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type SumEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumEntriesSince, arg.AccountID, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const updateEntry = `-- name: UpdateEntry :one
 
-UPDATE entries set amount = $2 WHERE id = $1 RETURNING id, account_id, amount, created_at
+UPDATE entries set amount = $2 WHERE id = $1 RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type UpdateEntryParams struct {
@@ -121,6 +283,7 @@ func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
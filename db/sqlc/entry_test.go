@@ -106,3 +106,51 @@ func TestListEntries(t *testing.T) {
 		require.Equal(t, entry.AccountID, account.ID)
 	}
 }
+
+func TestSearchEntries(t *testing.T) {
+	account := CreateRandomAccount(t)
+	amounts := []int64{-300, 100, 200, -50}
+	for _, amount := range amounts {
+		_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := testQueries.SearchEntries(context.Background(), SearchEntriesParams{
+		AccountID:  account.ID,
+		FromTime:   sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+		MinAmount:  sql.NullInt64{Int64: 100, Valid: true},
+		OrderBy:    "amount",
+		Descending: true,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, int64(-300), entries[0].Amount)
+	require.Equal(t, int64(200), entries[1].Amount)
+	require.Equal(t, int64(100), entries[2].Amount)
+
+	// the next page starts after the last entry seen, in the same order
+	entries, err = testQueries.SearchEntries(context.Background(), SearchEntriesParams{
+		AccountID:   account.ID,
+		AfterID:     sql.NullInt64{Int64: entries[1].ID, Valid: true},
+		AfterAmount: 200,
+		OrderBy:     "amount",
+		Descending:  true,
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, int64(100), entries[0].Amount)
+	require.Equal(t, int64(-50), entries[1].Amount)
+
+	entries, err = testQueries.SearchEntries(context.Background(), SearchEntriesParams{
+		AccountID: account.ID,
+		ToTime:    sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,123 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: exchange_rate.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createExchangeRate = `-- name: CreateExchangeRate :one
+
+INSERT INTO
+    exchange_rates (base_currency, quote_currency, rate, valid_from)
+VALUES ($1, $2, $3, $4) RETURNING id, base_currency, quote_currency, rate, valid_from, created_at
+`
+
+type CreateExchangeRateParams struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	ValidFrom     time.Time `json:"valid_from"`
+}
+
+func (q *Queries) CreateExchangeRate(ctx context.Context, arg CreateExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRowContext(ctx, createExchangeRate,
+		arg.BaseCurrency,
+		arg.QuoteCurrency,
+		arg.Rate,
+		arg.ValidFrom,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.ValidFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getExchangeRate = `-- name: GetExchangeRate :one
+
+SELECT id, base_currency, quote_currency, rate, valid_from, created_at FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2 AND valid_from <= $3
+ORDER BY valid_from DESC
+LIMIT 1
+`
+
+type GetExchangeRateParams struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	At            time.Time `json:"at"`
+}
+
+func (q *Queries) GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRowContext(ctx, getExchangeRate, arg.BaseCurrency, arg.QuoteCurrency, arg.At)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.ValidFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listExchangeRates = `-- name: ListExchangeRates :many
+
+SELECT id, base_currency, quote_currency, rate, valid_from, created_at FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2
+ORDER BY valid_from DESC
+LIMIT $3
+OFFSET $4
+`
+
+type ListExchangeRatesParams struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	Limit         int32  `json:"limit"`
+	Offset        int32  `json:"offset"`
+}
+
+func (q *Queries) ListExchangeRates(ctx context.Context, arg ListExchangeRatesParams) ([]ExchangeRate, error) {
+	rows, err := q.db.QueryContext(ctx, listExchangeRates,
+		arg.BaseCurrency,
+		arg.QuoteCurrency,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExchangeRate{}
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.BaseCurrency,
+			&i.QuoteCurrency,
+			&i.Rate,
+			&i.ValidFrom,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomExchangeRate(t *testing.T, validFrom time.Time) ExchangeRate {
+	arg := CreateExchangeRateParams{
+		BaseCurrency:  money.USD,
+		QuoteCurrency: util.RandomString(3),
+		Rate:          "1.25",
+		ValidFrom:     validFrom,
+	}
+
+	rate, err := testQueries.CreateExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, rate)
+
+	require.Equal(t, arg.BaseCurrency, rate.BaseCurrency)
+	require.Equal(t, arg.QuoteCurrency, rate.QuoteCurrency)
+	require.Equal(t, arg.Rate, rate.Rate)
+	require.WithinDuration(t, arg.ValidFrom, rate.ValidFrom, time.Second)
+	require.NotZero(t, rate.ID)
+	require.NotZero(t, rate.CreatedAt)
+	return rate
+}
+
+func TestCreateExchangeRate(t *testing.T) {
+	createRandomExchangeRate(t, time.Now())
+}
+
+func TestGetExchangeRate(t *testing.T) {
+	rate1 := createRandomExchangeRate(t, time.Now().Add(-time.Hour))
+
+	rate2, err := testQueries.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		BaseCurrency:  rate1.BaseCurrency,
+		QuoteCurrency: rate1.QuoteCurrency,
+		At:            time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, rate1.ID, rate2.ID)
+
+	_, err = testQueries.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		BaseCurrency:  rate1.BaseCurrency,
+		QuoteCurrency: rate1.QuoteCurrency,
+		At:            time.Now().Add(-2 * time.Hour),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
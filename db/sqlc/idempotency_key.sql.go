@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :one
+
+INSERT INTO
+    idempotency_keys (username, key, request_hash, expired_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (username, key) DO UPDATE
+SET
+    request_hash = EXCLUDED.request_hash,
+    response_status = 0,
+    response_body = '{}',
+    expired_at = EXCLUDED.expired_at,
+    created_at = now()
+WHERE idempotency_keys.expired_at < now()
+RETURNING username, key, request_hash, response_status, response_body, expired_at, created_at
+`
+
+type CreateIdempotencyKeyParams struct {
+	Username    string    `json:"username"`
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, createIdempotencyKey,
+		arg.Username,
+		arg.Key,
+		arg.RequestHash,
+		arg.ExpiredAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+
+DELETE FROM idempotency_keys WHERE expired_at < now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+
+DELETE FROM idempotency_keys WHERE username = $1 AND key = $2
+`
+
+type DeleteIdempotencyKeyParams struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteIdempotencyKey, arg.Username, arg.Key)
+	return err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+
+SELECT username, key, request_hash, response_status, response_body, expired_at, created_at FROM idempotency_keys WHERE username = $1 AND key = $2 LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Username, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateIdempotencyKeyResponse = `-- name: UpdateIdempotencyKeyResponse :one
+
+UPDATE idempotency_keys
+SET
+    response_status = $1,
+    response_body = $2
+WHERE username = $3 AND key = $4
+RETURNING username, key, request_hash, response_status, response_body, expired_at, created_at
+`
+
+type UpdateIdempotencyKeyResponseParams struct {
+	ResponseStatus int32           `json:"response_status"`
+	ResponseBody   json.RawMessage `json:"response_body"`
+	Username       string          `json:"username"`
+	Key            string          `json:"key"`
+}
+
+func (q *Queries) UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, updateIdempotencyKeyResponse,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.Username,
+		arg.Key,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
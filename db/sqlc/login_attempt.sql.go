@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: login_attempt.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const deleteLoginAttempt = `-- name: DeleteLoginAttempt :exec
+
+DELETE FROM login_attempts WHERE key = $1
+`
+
+func (q *Queries) DeleteLoginAttempt(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginAttempt, key)
+	return err
+}
+
+const getLoginAttempt = `-- name: GetLoginAttempt :one
+
+SELECT key, failures, last_failed_at FROM login_attempts WHERE key = $1 LIMIT 1
+`
+
+func (q *Queries) GetLoginAttempt(ctx context.Context, key string) (LoginAttempt, error) {
+	row := q.db.QueryRowContext(ctx, getLoginAttempt, key)
+	var i LoginAttempt
+	err := row.Scan(&i.Key, &i.Failures, &i.LastFailedAt)
+	return i, err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+
+WITH previous AS (
+  SELECT last_failed_at FROM login_attempts WHERE key = $1 FOR UPDATE
+)
+INSERT INTO login_attempts (key, failures, last_failed_at)
+VALUES ($1, 1, $2)
+ON CONFLICT (key) DO UPDATE
+  SET failures = CASE
+        WHEN login_attempts.last_failed_at < $3 THEN 1
+        ELSE login_attempts.failures + 1
+      END,
+      last_failed_at = EXCLUDED.last_failed_at
+RETURNING login_attempts.key, login_attempts.failures, login_attempts.last_failed_at,
+  COALESCE((SELECT last_failed_at FROM previous), $2)::timestamptz AS previous_failed_at
+`
+
+type RecordLoginFailureParams struct {
+	Key          string    `json:"key"`
+	FailedAt     time.Time `json:"failed_at"`
+	ForgetBefore time.Time `json:"forget_before"`
+}
+
+type RecordLoginFailureRow struct {
+	Key              string    `json:"key"`
+	Failures         int32     `json:"failures"`
+	LastFailedAt     time.Time `json:"last_failed_at"`
+	PreviousFailedAt time.Time `json:"previous_failed_at"`
+}
+
+// counts a failed login, failures before forget_before are dropped first. previous_failed_at
+// is the failure before this one, or failed_at for new keys, see RefundLoginFailure
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (RecordLoginFailureRow, error) {
+	row := q.db.QueryRowContext(ctx, recordLoginFailure, arg.Key, arg.FailedAt, arg.ForgetBefore)
+	var i RecordLoginFailureRow
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailedAt,
+		&i.PreviousFailedAt,
+	)
+	return i, err
+}
+
+const refundLoginFailure = `-- name: RefundLoginFailure :exec
+
+UPDATE login_attempts SET
+  failures = failures - 1,
+  last_failed_at = CASE
+    WHEN last_failed_at = $1 THEN $2
+    ELSE last_failed_at
+  END
+WHERE key = $3 AND failures > 0
+`
+
+type RefundLoginFailureParams struct {
+	FailedAt         time.Time `json:"failed_at"`
+	PreviousFailedAt time.Time `json:"previous_failed_at"`
+	Key              string    `json:"key"`
+}
+
+// takes back a failure counted ahead of a login that turned out right. last_failed_at goes back
+// to previous_failed_at unless another failure was counted after failed_at.
+func (q *Queries) RefundLoginFailure(ctx context.Context, arg RefundLoginFailureParams) error {
+	_, err := q.db.ExecContext(ctx, refundLoginFailure, arg.FailedAt, arg.PreviousFailedAt, arg.Key)
+	return err
+}
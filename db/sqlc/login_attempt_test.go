@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoginFailure(t *testing.T) {
+	key := "user:" + util.RandomOwner()
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		attempt, err := testQueries.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+			Key:          key,
+			FailedAt:     now,
+			ForgetBefore: now.Add(-time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(i), attempt.Failures)
+		require.WithinDuration(t, now, attempt.LastFailedAt, time.Millisecond)
+	}
+
+	// failures older than forget_before start the count over
+	later := now.Add(2 * time.Hour)
+	attempt, err := testQueries.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+		Key:          key,
+		FailedAt:     later,
+		ForgetBefore: later.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), attempt.Failures)
+
+	require.WithinDuration(t, now, attempt.PreviousFailedAt, time.Millisecond)
+
+	// refunds put back the previous failure time and never take the count below zero
+	for i := 0; i < 2; i++ {
+		err = testQueries.RefundLoginFailure(context.Background(), RefundLoginFailureParams{
+			Key:              key,
+			FailedAt:         attempt.LastFailedAt,
+			PreviousFailedAt: attempt.PreviousFailedAt,
+		})
+		require.NoError(t, err)
+	}
+	refunded, err := testQueries.GetLoginAttempt(context.Background(), key)
+	require.NoError(t, err)
+	require.Zero(t, refunded.Failures)
+	require.WithinDuration(t, now, refunded.LastFailedAt, time.Millisecond)
+
+	err = testQueries.DeleteLoginAttempt(context.Background(), key)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetLoginAttempt(context.Background(), key)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRecordLoginFailureConcurrent(t *testing.T) {
+	key := "ip:" + util.RandomString(12)
+	now := time.Now()
+
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := testQueries.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+				Key:          key,
+				FailedAt:     now,
+				ForgetBefore: now.Add(-time.Hour),
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	attempt, err := testQueries.GetLoginAttempt(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, int32(n), attempt.Failures)
+}
@@ -5,17 +5,128 @@
 package db
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus `json:"account_status"`
+	Valid         bool          `json:"valid"` // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountStatus), nil
+}
+
+type UserRole string
+
+const (
+	UserRoleDepositor UserRole = "depositor"
+	UserRoleBanker    UserRole = "banker"
+	UserRoleAdmin     UserRole = "admin"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole `json:"user_role"`
+	Valid    bool     `json:"valid"` // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
+}
+
 type Account struct {
 	ID        int64     `json:"id"`
 	Owner     string    `json:"owner"`
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// how far below zero the balance is allowed to go
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// only active accounts can be debited or credited, closed is final
+	Status AccountStatus `json:"status"`
+	// the user who froze the account, null unless it is frozen
+	FrozenBy sql.NullString `json:"frozen_by"`
+	FrozenAt sql.NullTime   `json:"frozen_at"`
+}
+
+type BalanceAdjustment struct {
+	ID                int64 `json:"id"`
+	AccountID         int64 `json:"account_id"`
+	SuspenseAccountID int64 `json:"suspense_account_id"`
+	// credited to the account when positive, debited when negative
+	Amount int64  `json:"amount"`
+	Reason string `json:"reason"`
+	// admin who made the adjustment
+	Operator        string    `json:"operator"`
+	EntryID         int64     `json:"entry_id"`
+	SuspenseEntryID int64     `json:"suspense_entry_id"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,6 +135,45 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer that created the entry, entries written before this column existed are not linked
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type ExchangeRate struct {
+	ID            int64  `json:"id"`
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// quote amount received for one unit of base amount
+	Rate      string    `json:"rate"`
+	ValidFrom time.Time `json:"valid_from"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username    string `json:"username"`
+	Key         string `json:"key"`
+	RequestHash string `json:"request_hash"`
+	// 0 while the original request is still in flight
+	ResponseStatus int32           `json:"response_status"`
+	ResponseBody   json.RawMessage `json:"response_body"`
+	ExpiredAt      time.Time       `json:"expired_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	// user:<username> or ip:<client ip>
+	Key          string    `json:"key"`
+	Failures     int32     `json:"failures"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+}
+
+type PasswordReset struct {
+	ID         int64     `json:"id"`
+	Username   string    `json:"username"`
+	SecretCode string    `json:"secret_code"`
+	IsUsed     bool      `json:"is_used"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
 }
 
 type Session struct {
@@ -35,15 +185,36 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiredAt    time.Time `json:"expired_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// id of the session created at login, shared by all of its rotations
+	FamilyID uuid.UUID `json:"family_id"`
+	// session whose refresh token was exchanged for this one
+	ParentID uuid.NullUUID `json:"parent_id"`
+	// set once the refresh token has been exchanged, it must not be used again
+	RotatedAt sql.NullTime `json:"rotated_at"`
+}
+
+type TotpRecoveryCode struct {
+	ID         int64        `json:"id"`
+	Username   string       `json:"username"`
+	HashedCode string       `json:"hashed_code"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
 }
 
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	// must be positive
+	// must be positive, in the currency of the from account
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// must be positive, in the currency of the to account
+	ToAmount     int64  `json:"to_amount"`
+	ExchangeRate string `json:"exchange_rate"`
+	// transfer compensated by this one, reversals cannot be reversed themselves
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
+	// part of amount already paid back by reversals, in the currency of the from account
+	ReversedAmount int64 `json:"reversed_amount"`
 }
 
 type User struct {
@@ -53,4 +224,28 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// bankers can view every account, admins additionally manage users and adjustments
+	Role            UserRole `json:"role"`
+	IsEmailVerified bool     `json:"is_email_verified"`
+}
+
+type UserTotp struct {
+	Username string `json:"username"`
+	Secret   string `json:"secret"`
+	// two-factor authentication is enabled once the first code of the secret is confirmed
+	ConfirmedAt sql.NullTime `json:"confirmed_at"`
+	// time step of the last accepted code, codes of this step or an earlier one cannot be replayed
+	LastUsedStep int64     `json:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type VerifyEmail struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// address the code was sent to, it only verifies the user while this is still their email
+	Email      string    `json:"email"`
+	SecretCode string    `json:"secret_code"`
+	IsUsed     bool      `json:"is_used"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
 }
@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: password_reset.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createPasswordReset = `-- name: CreatePasswordReset :one
+
+INSERT INTO
+    password_resets (username, secret_code, expired_at)
+VALUES ($1, $2, $3) RETURNING id, username, secret_code, is_used, created_at, expired_at
+`
+
+type CreatePasswordResetParams struct {
+	Username   string    `json:"username"`
+	SecretCode string    `json:"secret_code"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, createPasswordReset, arg.Username, arg.SecretCode, arg.ExpiredAt)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const expireUserPasswordResets = `-- name: ExpireUserPasswordResets :execrows
+
+UPDATE password_resets SET is_used = TRUE
+WHERE username = $1 AND is_used = FALSE
+`
+
+func (q *Queries) ExpireUserPasswordResets(ctx context.Context, username string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireUserPasswordResets, username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const usePasswordReset = `-- name: UsePasswordReset :one
+
+UPDATE password_resets SET is_used = TRUE
+WHERE id = $1
+  AND secret_code = $2
+  AND is_used = FALSE
+  AND expired_at > now()
+RETURNING id, username, secret_code, is_used, created_at, expired_at
+`
+
+type UsePasswordResetParams struct {
+	ID         int64  `json:"id"`
+	SecretCode string `json:"secret_code"`
+}
+
+// marks an unused and unexpired code as used, no row means the code is not valid
+func (q *Queries) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, usePasswordReset, arg.ID, arg.SecretCode)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
@@ -6,32 +6,105 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error)
+	BlockSession(ctx context.Context, arg BlockSessionParams) (Session, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
+	// frozen accounts are only closed when close_frozen is set, for staff
+	CloseAccount(ctx context.Context, arg CloseAccountParams) (Account, error)
+	ConfirmUserTotp(ctx context.Context, arg ConfirmUserTotpParams) (UserTotp, error)
+	CountLedger(ctx context.Context) (CountLedgerRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExchangeRate(ctx context.Context, arg CreateExchangeRateParams) (ExchangeRate, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSuspenseAccount(ctx context.Context, arg CreateSuspenseAccountParams) error
+	CreateTotpRecoveryCode(ctx context.Context, arg CreateTotpRecoveryCodeParams) (TotpRecoveryCode, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
 	DeleteEntry(ctx context.Context, id int64) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteLoginAttempt(ctx context.Context, key string) error
+	DeleteTotpRecoveryCodes(ctx context.Context, username string) error
 	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteUserTotp(ctx context.Context, username string) error
+	ExpireUserPasswordResets(ctx context.Context, username string) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByOwnerAndCurrency(ctx context.Context, arg GetAccountByOwnerAndCurrencyParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetBalanceAdjustment(ctx context.Context, id int64) (BalanceAdjustment, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetLoginAttempt(ctx context.Context, key string) (LoginAttempt, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error)
+	GetUserTotp(ctx context.Context, username string) (UserTotp, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]Session, error)
+	ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]ListBalanceAdjustmentsRow, error)
+	// accounts whose balance is not the sum of their entries
+	ListBalanceDrifts(ctx context.Context) ([]ListBalanceDriftsRow, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListExchangeRates(ctx context.Context, arg ListExchangeRatesParams) ([]ExchangeRate, error)
+	// entries written neither by a transfer nor by a balance adjustment
+	ListOrphanedEntries(ctx context.Context) ([]ListOrphanedEntriesRow, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListTransferReversals(ctx context.Context, reversalOf sql.NullInt64) ([]Transfer, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// transfers without exactly one debit of amount on the from account
+	// and one credit of to_amount on the to account
+	ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error)
+	// counts a failed login, failures before forget_before are dropped first. previous_failed_at
+	// is the failure before this one, or failed_at for new keys, see RefundLoginFailure
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (RecordLoginFailureRow, error)
+	// takes back a failure counted ahead of a login that turned out right. last_failed_at goes back
+	// to previous_failed_at unless another failure was counted after failed_at.
+	RefundLoginFailure(ctx context.Context, arg RefundLoginFailureParams) error
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SearchEntries(ctx context.Context, arg SearchEntriesParams) ([]Entry, error)
+	// amounts are compared in the currency of the given account:
+	// what it sent for outgoing transfers, what it received for incoming ones
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error)
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
 	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
 	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
+	// only the given fields change, a new email is not verified anymore
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	// starts or restarts an enrollment, no row means two-factor authentication is already enabled
+	UpsertPendingUserTotp(ctx context.Context, arg UpsertPendingUserTotpParams) (UserTotp, error)
+	// marks an unused and unexpired code as used, no row means the code is not valid
+	UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error)
+	UseTotpRecoveryCode(ctx context.Context, arg UseTotpRecoveryCodeParams) (TotpRecoveryCode, error)
+	// accepts a code once, no row means a code of this step or a later one was already used
+	UseUserTotpStep(ctx context.Context, arg UseUserTotpStepParams) (UserTotp, error)
+	// marks an unused and unexpired code as used, no row means the code is not valid
+	UseVerifyEmail(ctx context.Context, arg UseVerifyEmailParams) (VerifyEmail, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,208 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: reconciliation.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const countLedger = `-- name: CountLedger :one
+
+SELECT
+  (SELECT count(*) FROM accounts) AS accounts,
+  (SELECT count(*) FROM entries) AS entries,
+  (SELECT count(*) FROM transfers) AS transfers
+`
+
+type CountLedgerRow struct {
+	Accounts  int64 `json:"accounts"`
+	Entries   int64 `json:"entries"`
+	Transfers int64 `json:"transfers"`
+}
+
+func (q *Queries) CountLedger(ctx context.Context) (CountLedgerRow, error) {
+	row := q.db.QueryRowContext(ctx, countLedger)
+	var i CountLedgerRow
+	err := row.Scan(&i.Accounts, &i.Entries, &i.Transfers)
+	return i, err
+}
+
+const listBalanceDrifts = `-- name: ListBalanceDrifts :many
+
+SELECT accounts.id, accounts.owner, accounts.currency, accounts.balance,
+  COALESCE(SUM(entries.amount), 0)::bigint AS entries_total
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+GROUP BY accounts.id
+HAVING accounts.balance <> COALESCE(SUM(entries.amount), 0)
+ORDER BY accounts.id
+`
+
+type ListBalanceDriftsRow struct {
+	ID           int64  `json:"id"`
+	Owner        string `json:"owner"`
+	Currency     string `json:"currency"`
+	Balance      int64  `json:"balance"`
+	EntriesTotal int64  `json:"entries_total"`
+}
+
+// accounts whose balance is not the sum of their entries
+func (q *Queries) ListBalanceDrifts(ctx context.Context) ([]ListBalanceDriftsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBalanceDrifts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBalanceDriftsRow{}
+	for rows.Next() {
+		var i ListBalanceDriftsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Currency,
+			&i.Balance,
+			&i.EntriesTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanedEntries = `-- name: ListOrphanedEntries :many
+
+SELECT entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id, accounts.currency FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE entries.transfer_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM balance_adjustments
+    WHERE balance_adjustments.entry_id = entries.id OR balance_adjustments.suspense_entry_id = entries.id
+  )
+ORDER BY entries.id
+`
+
+type ListOrphanedEntriesRow struct {
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	CreatedAt  time.Time     `json:"created_at"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	Currency   string        `json:"currency"`
+}
+
+// entries written neither by a transfer nor by a balance adjustment
+func (q *Queries) ListOrphanedEntries(ctx context.Context) ([]ListOrphanedEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrphanedEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrphanedEntriesRow{}
+	for rows.Next() {
+		var i ListOrphanedEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbalancedTransfers = `-- name: ListUnbalancedTransfers :many
+
+SELECT transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.to_amount, transfers.exchange_rate, transfers.reversal_of, transfers.reversed_amount, from_account.currency AS from_currency, to_account.currency AS to_currency,
+  count(entries.id) AS entry_count,
+  count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.from_account_id AND entries.amount = -transfers.amount
+  ) AS debit_count,
+  count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.to_account_id AND entries.amount = transfers.to_amount
+  ) AS credit_count
+FROM transfers
+JOIN accounts from_account ON from_account.id = transfers.from_account_id
+JOIN accounts to_account ON to_account.id = transfers.to_account_id
+LEFT JOIN entries ON entries.transfer_id = transfers.id
+GROUP BY transfers.id, from_account.currency, to_account.currency
+HAVING count(entries.id) <> 2
+  OR count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.from_account_id AND entries.amount = -transfers.amount
+  ) <> 1
+  OR count(entries.id) FILTER (
+    WHERE entries.account_id = transfers.to_account_id AND entries.amount = transfers.to_amount
+  ) <> 1
+ORDER BY transfers.id
+`
+
+type ListUnbalancedTransfersRow struct {
+	Transfer     Transfer `json:"transfer"`
+	FromCurrency string   `json:"from_currency"`
+	ToCurrency   string   `json:"to_currency"`
+	EntryCount   int64    `json:"entry_count"`
+	DebitCount   int64    `json:"debit_count"`
+	CreditCount  int64    `json:"credit_count"`
+}
+
+// transfers without exactly one debit of amount on the from account
+// and one credit of to_amount on the to account
+func (q *Queries) ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnbalancedTransfers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnbalancedTransfersRow{}
+	for rows.Next() {
+		var i ListUnbalancedTransfersRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.ToAmount,
+			&i.Transfer.ExchangeRate,
+			&i.Transfer.ReversalOf,
+			&i.Transfer.ReversedAmount,
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.EntryCount,
+			&i.DebitCount,
+			&i.CreditCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
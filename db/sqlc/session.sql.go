@@ -7,26 +7,88 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const blockSession = `-- name: BlockSession :one
+
+UPDATE sessions SET is_blocked = true
+WHERE id = $1 AND username = $2
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, parent_id, rotated_at
+`
+
+type BlockSessionParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+func (q *Queries) BlockSession(ctx context.Context, arg BlockSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, blockSession, arg.ID, arg.Username)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :execrows
+
+UPDATE sessions SET is_blocked = true
+WHERE family_id = $1 AND is_blocked = false
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockSessionFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :execrows
+
+UPDATE sessions SET is_blocked = true
+WHERE username = $1 AND is_blocked = false
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createSession = `-- name: CreateSession :one
 
 INSERT INTO
-    sessions (id,username,refresh_token,user_agent,client_ip,is_blocked,expired_at)
-VALUES ($1, $2, $3, $4,$5,$6,$7) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at
+    sessions (id,username,refresh_token,user_agent,client_ip,is_blocked,expired_at,family_id,parent_id)
+VALUES ($1, $2, $3, $4,$5,$6,$7,$8,$9) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, parent_id, rotated_at
 `
 
 type CreateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiredAt    time.Time `json:"expired_at"`
+	ID           uuid.UUID     `json:"id"`
+	Username     string        `json:"username"`
+	RefreshToken string        `json:"refresh_token"`
+	UserAgent    string        `json:"user_agent"`
+	ClientIp     string        `json:"client_ip"`
+	IsBlocked    bool          `json:"is_blocked"`
+	ExpiredAt    time.Time     `json:"expired_at"`
+	FamilyID     uuid.UUID     `json:"family_id"`
+	ParentID     uuid.NullUUID `json:"parent_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -38,6 +100,8 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiredAt,
+		arg.FamilyID,
+		arg.ParentID,
 	)
 	var i Session
 	err := row.Scan(
@@ -49,13 +113,16 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
 
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at FROM sessions WHERE id = $1 LIMIT 1
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, parent_id, rotated_at FROM sessions WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
@@ -70,6 +137,92 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, parent_id, rotated_at FROM sessions
+WHERE username = $1 AND is_blocked = false AND rotated_at IS NULL AND expired_at > now()
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) < ($2, $3::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListActiveSessionsParams struct {
+	Username       string        `json:"username"`
+	AfterCreatedAt sql.NullTime  `json:"after_created_at"`
+	AfterID        uuid.NullUUID `json:"after_id"`
+	Limit          int32         `json:"limit"`
+}
+
+func (q *Queries) ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions,
+		arg.Username,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.ParentID,
+			&i.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+
+UPDATE sessions SET rotated_at = now()
+WHERE id = $1 AND rotated_at IS NULL
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, parent_id, rotated_at
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
@@ -3,18 +3,45 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// ErrInsufficientFunds is returned when a transfer would take the source account below its overdraft limit
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrAccountNotActive is returned when money would move in or out of a frozen or closed account
+var ErrAccountNotActive = errors.New("account is not active")
+
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	CreateExchangeRatesTx(ctx context.Context, arg []CreateExchangeRateParams) ([]ExchangeRate, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error)
+	ReconcileTx(ctx context.Context) (ReconcileTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ChangePasswordTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	ConfirmTOTPTx(ctx context.Context, arg ConfirmTOTPTxParams) (UserTotp, error)
+	DisableTOTPTx(ctx context.Context, username string) error
+	TxStats() TxStats
 }
 
 // Store provides all functions to execute db queries and transactions
 type SQLStore struct {
 	*Queries
-	db *sql.DB
+	db      *sql.DB
+	txStats txCounters
 }
 
 func NewStore(db *sql.DB) Store {
@@ -24,9 +51,98 @@ func NewStore(db *sql.DB) Store {
 	}
 }
 
-// execTx executes a function within a database transaction
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+const (
+	// maxTxAttempts bounds how many times a transaction is run when it keeps failing with a retryable error
+	maxTxAttempts    = 5
+	txRetryBaseDelay = 10 * time.Millisecond
+	txRetryMaxDelay  = 200 * time.Millisecond
+)
+
+// TxStats reports how often transactions had to be retried
+type TxStats struct {
+	Attempts              uint64 `json:"attempts"`
+	Retries               uint64 `json:"retries"`
+	SerializationFailures uint64 `json:"serialization_failures"`
+	Deadlocks             uint64 `json:"deadlocks"`
+	// Exhausted counts transactions that still failed after maxTxAttempts
+	Exhausted uint64 `json:"exhausted"`
+}
+
+type txCounters struct {
+	attempts              atomic.Uint64
+	retries               atomic.Uint64
+	serializationFailures atomic.Uint64
+	deadlocks             atomic.Uint64
+	exhausted             atomic.Uint64
+}
+
+// TxStats returns the transaction counters since the store was created
+func (store *SQLStore) TxStats() TxStats {
+	return TxStats{
+		Attempts:              store.txStats.attempts.Load(),
+		Retries:               store.txStats.retries.Load(),
+		SerializationFailures: store.txStats.serializationFailures.Load(),
+		Deadlocks:             store.txStats.deadlocks.Load(),
+		Exhausted:             store.txStats.exhausted.Load(),
+	}
+}
+
+// execTx executes a function within a database transaction.
+// The transaction is run again from scratch when Postgres aborts it because of a serialization failure
+// or a deadlock, so fn must not keep state from a previous attempt.
+func (store *SQLStore) execTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	for attempt := 1; ; attempt++ {
+		store.txStats.attempts.Add(1)
+
+		err := store.runTx(ctx, opts, fn)
+		if !store.recordRetryable(err) {
+			return err
+		}
+
+		if attempt == maxTxAttempts {
+			store.txStats.exhausted.Add(1)
+			return fmt.Errorf("tx failed after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(txRetryDelay(attempt)):
+		}
+		store.txStats.retries.Add(1)
+	}
+}
+
+// recordRetryable reports whether err aborted the transaction in a way that is safe to retry
+func (store *SQLStore) recordRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code.Name() {
+	case "serialization_failure":
+		store.txStats.serializationFailures.Add(1)
+		return true
+	case "deadlock_detected":
+		store.txStats.deadlocks.Add(1)
+		return true
+	}
+	return false
+}
+
+// txRetryDelay doubles the delay after each attempt, up to txRetryMaxDelay.
+// Half of it is randomized so that conflicting transactions don't retry in lockstep.
+func txRetryDelay(attempt int) time.Duration {
+	delay := txRetryMaxDelay
+	if shift := attempt - 1; shift < 16 && txRetryBaseDelay<<shift < txRetryMaxDelay {
+		delay = txRetryBaseDelay << shift
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (store *SQLStore) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 
 	if err != nil {
 		return err
@@ -37,7 +153,7 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("tx errr: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
 		}
 		return err
 	}
@@ -48,6 +164,10 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// ToAmount and ExchangeRate are only set for cross-currency transfers,
+	// otherwise the to account is credited with Amount at a rate of 1
+	ToAmount     int64  `json:"to_amount"`
+	ExchangeRate string `json:"exchange_rate"`
 }
 
 type TransferTxResult struct {
@@ -63,44 +183,80 @@ var txKey = struct{}{}
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, nil, func(q *Queries) error {
 		var err error
+		result, err = transfer(ctx, q, arg, sql.NullInt64{})
+		return err
+	})
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+// transfer books a transfer with its two entries and moves the money. reversalOf links
+// the transfer to the one it compensates.
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams, reversalOf sql.NullInt64) (TransferTxResult, error) {
+	var result TransferTxResult
 
-		result.ToEnTry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	toAmount, exchangeRate := arg.ToAmount, arg.ExchangeRate
+	if toAmount == 0 {
+		toAmount, exchangeRate = arg.Amount, "1"
+	}
 
-		if arg.FromAccountID > arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = AddMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
+	var err error
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		ToAmount:      toAmount,
+		ExchangeRate:  exchangeRate,
+		ReversalOf:    reversalOf,
+	})
+	if err != nil {
+		return result, err
+	}
 
-		return nil
+	transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccountID,
+		Amount:     -arg.Amount,
+		TransferID: transferID,
 	})
+	if err != nil {
+		return result, err
+	}
 
-	return result, err
+	result.ToEnTry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccountID,
+		Amount:     toAmount,
+		TransferID: transferID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if arg.FromAccountID > arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, toAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = AddMoney(ctx, q, arg.ToAccountID, toAmount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// the updates above hold the row locks of both accounts, so checking their status and
+	// the new balance here and rolling back is equivalent to guarding the updates themselves
+	for _, account := range []Account{result.FromAccount, result.ToAccount} {
+		if account.Status != AccountStatusActive {
+			return result, fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+		}
+	}
+
+	if result.FromAccount.Balance < -result.FromAccount.OverdraftLimit {
+		return result, fmt.Errorf("%w: account [%d] balance %d is below overdraft limit %d",
+			ErrInsufficientFunds, arg.FromAccountID, result.FromAccount.Balance, result.FromAccount.OverdraftLimit)
+	}
+
+	return result, nil
 }
 
 func AddMoney(ctx context.Context, q *Queries, account1ID int64, amount1 int64, account2ID int64, amount2 int64) (account1 Account, account2 Account, err error) {
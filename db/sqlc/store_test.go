@@ -2,19 +2,26 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := CreateRandomAccount(t)
-	account2 := CreateRandomAccount(t)
 	// run n concurrent transfer transactions
 	n := 10
 	amount := int64(10)
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount+util.RandomMoney())
+	account2 := CreateRandomAccount(t)
 	errs := make(chan error)
 	results := make(chan TransferTxResult)
 
@@ -109,11 +116,11 @@ func TestTransferTx(t *testing.T) {
 func TestTransferDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := CreateRandomAccount(t)
-	account2 := CreateRandomAccount(t)
 	// run n concurrent transfer transactions
 	n := 20
 	amount := int64(10)
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount)
+	account2 := createRandomAccountWithBalance(t, int64(n)*amount)
 	errs := make(chan error)
 
 	for i := 0; i < n; i++ {
@@ -152,3 +159,708 @@ func TestTransferDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 10)
+	account2 := CreateRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrInsufficientFunds))
+
+	// the whole transaction must be rolled back
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestTransferTxOverdraftLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 10)
+	account2 := CreateRandomAccount(t)
+
+	account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: 50,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-50), result.FromAccount.Balance)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.True(t, errors.Is(err, ErrInsufficientFunds))
+}
+
+func TestExecTxSerializable(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+	serializable := &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+	// move money back and forth with read-modify-write transactions,
+	// which Postgres aborts with serialization failures under contention
+	n := 10
+	amount := int64(10)
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount)
+	account2 := createRandomAccountWithBalance(t, int64(n)*amount)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccountID := account1.ID
+		toAccountID := account2.ID
+
+		if i%2 == 1 {
+			fromAccountID = account2.ID
+			toAccountID = account1.ID
+		}
+
+		go func() {
+			errs <- store.execTx(context.Background(), serializable, func(q *Queries) error {
+				fromAccount, err := q.GetAccount(context.Background(), fromAccountID)
+				if err != nil {
+					return err
+				}
+				toAccount, err := q.GetAccount(context.Background(), toAccountID)
+				if err != nil {
+					return err
+				}
+
+				_, err = q.UpdateAccount(context.Background(), UpdateAccountParams{ID: fromAccountID, Balance: fromAccount.Balance - amount})
+				if err != nil {
+					return err
+				}
+				_, err = q.UpdateAccount(context.Background(), UpdateAccountParams{ID: toAccountID, Balance: toAccount.Balance + amount})
+				return err
+			})
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			// a transaction may still lose every attempt under heavy contention
+			require.ErrorContains(t, err, fmt.Sprintf("after %d attempts", maxTxAttempts))
+		}
+	}
+
+	stats := store.TxStats()
+	require.GreaterOrEqual(t, stats.Attempts, uint64(n))
+	require.Equal(t, stats.Attempts-uint64(n), stats.Retries+stats.Exhausted)
+
+	// whatever committed, no money was created or destroyed
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance+account2.Balance, updatedAccount1.Balance+updatedAccount2.Balance)
+}
+
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+
+	attempts := 0
+	err := store.execTx(context.Background(), nil, func(q *Queries) error {
+		attempts++
+		if attempts < 3 {
+			return &pq.Error{Code: "40P01"}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	stats := store.TxStats()
+	require.Equal(t, uint64(3), stats.Attempts)
+	require.Equal(t, uint64(2), stats.Retries)
+	require.Equal(t, uint64(2), stats.Deadlocks)
+}
+
+func TestExecTxRetryExhausted(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+
+	attempts := 0
+	err := store.execTx(context.Background(), nil, func(q *Queries) error {
+		attempts++
+		return fmt.Errorf("wrapped: %w", &pq.Error{Code: "40001"})
+	})
+	require.Error(t, err)
+	require.Equal(t, maxTxAttempts, attempts)
+
+	var pqErr *pq.Error
+	require.True(t, errors.As(err, &pqErr))
+	require.Equal(t, uint64(1), store.TxStats().Exhausted)
+	require.Equal(t, uint64(maxTxAttempts), store.TxStats().SerializationFailures)
+}
+
+func TestExecTxNoRetry(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+
+	attempts := 0
+	err := store.execTx(context.Background(), &sql.TxOptions{ReadOnly: true}, func(q *Queries) error {
+		attempts++
+		_, err := q.CreateUser(context.Background(), CreateUserParams{
+			Username:       util.RandomUsername(),
+			HashedPassword: util.RandomHashedPassword(),
+			FullName:       util.RandomFullname(),
+			Email:          util.RandomEmail(),
+		})
+		return err
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+	require.Zero(t, store.TxStats().Retries)
+}
+
+func TestTxRetryDelay(t *testing.T) {
+	for attempt := 1; attempt < 100; attempt++ {
+		delay := txRetryDelay(attempt)
+		require.Greater(t, delay, time.Duration(0))
+		require.LessOrEqual(t, delay, txRetryMaxDelay)
+	}
+	require.LessOrEqual(t, txRetryDelay(1), txRetryBaseDelay)
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	newSessionParams := func() CreateSessionParams {
+		return CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			UserAgent:    util.RandomString(10),
+			ClientIp:     "127.0.0.1",
+			ExpiredAt:    time.Now().Add(time.Hour),
+		}
+	}
+
+	arg := newSessionParams()
+	arg.FamilyID = arg.ID
+	session1, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+
+	session2, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		ParentID:   session1.ID,
+		NewSession: newSessionParams(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, session2.FamilyID)
+	require.Equal(t, session1.ID, session2.ParentID.UUID)
+	require.False(t, session2.RotatedAt.Valid)
+
+	rotated, err := testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.RotatedAt.Valid)
+
+	// the parent can't be rotated a second time
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		ParentID:   session1.ID,
+		NewSession: newSessionParams(),
+	})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	blocked, err := testQueries.BlockSessionFamily(context.Background(), session1.FamilyID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+}
+
+func TestAdjustBalanceTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	operator := CreateRandomUser(t)
+	account := createRandomAccountWithBalance(t, 100)
+
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    25,
+		Reason:    "goodwill credit",
+		Operator:  operator.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+25, result.Account.Balance)
+	require.Equal(t, SuspenseAccountOwner, result.SuspenseAccount.Owner)
+	require.Equal(t, account.Currency, result.SuspenseAccount.Currency)
+	require.Equal(t, int64(25), result.Entry.Amount)
+	require.Equal(t, int64(-25), result.SuspenseEntry.Amount)
+	require.Equal(t, result.Entry.ID, result.Adjustment.EntryID)
+	require.Equal(t, result.SuspenseEntry.ID, result.Adjustment.SuspenseEntryID)
+	require.Equal(t, operator.Username, result.Adjustment.Operator)
+
+	// debits keep the overdraft limit of the account
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    -1000,
+		Reason:    "too much",
+		Operator:  operator.Username,
+	})
+	require.True(t, errors.Is(err, ErrInsufficientFunds))
+
+	adjustments, err := testQueries.ListBalanceAdjustments(context.Background(), ListBalanceAdjustmentsParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	require.Equal(t, result.Adjustment.ID, adjustments[0].BalanceAdjustment.ID)
+	require.Equal(t, account.Currency, adjustments[0].Currency)
+}
+
+func TestTransferTxAccountNotActive(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := CreateRandomAccount(t)
+
+	_, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account2.ID,
+		FromStatus: AccountStatusActive,
+		Status:     AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	// a frozen account can neither be credited nor debited
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.True(t, errors.Is(err, ErrAccountNotActive))
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.True(t, errors.Is(err, ErrAccountNotActive))
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := CreateRandomAccount(t)
+	from := time.Now().Add(-time.Second)
+
+	transfer1, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, transfer1.Transfer.ID, transfer1.FromEntry.TransferID.Int64)
+	require.Equal(t, transfer1.Transfer.ID, transfer1.ToEnTry.TransferID.Int64)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        5,
+	})
+	require.NoError(t, err)
+	to := time.Now()
+
+	// entries after the period only move the closing balance back from accounts.balance
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	result, err := store.StatementTx(context.Background(), StatementTxParams{
+		AccountID: account1.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(65), result.Account.Balance)
+	require.Equal(t, int64(100), result.OpeningBalance)
+	require.Equal(t, int64(75), result.ClosingBalance)
+	require.Len(t, result.Entries, 2)
+
+	require.Equal(t, int64(-30), result.Entries[0].Entry.Amount)
+	require.Equal(t, account1.ID, result.Entries[0].TransferFromAccountID.Int64)
+	require.Equal(t, account2.ID, result.Entries[0].TransferToAccountID.Int64)
+	require.Equal(t, int64(5), result.Entries[1].Entry.Amount)
+	require.Equal(t, account2.ID, result.Entries[1].TransferFromAccountID.Int64)
+	require.False(t, result.Entries[1].AdjustmentReason.Valid)
+}
+
+func TestReconcileTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 0)
+	account2 := createRandomAccountWithBalance(t, 0)
+
+	balanced, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// a transfer without entries, an entry without transfer and a balance without entries
+	unbalanced := createRandomTransfer(t, account1, account2)
+	orphan, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account2.ID,
+		Amount:    7,
+	})
+	require.NoError(t, err)
+	drifted := createRandomAccountWithBalance(t, 25)
+
+	result, err := store.ReconcileTx(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, result.Counts.Accounts)
+	require.NotZero(t, result.Counts.Entries)
+	require.NotZero(t, result.Counts.Transfers)
+
+	driftedIDs := map[int64]ListBalanceDriftsRow{}
+	for _, row := range result.BalanceDrifts {
+		driftedIDs[row.ID] = row
+	}
+	require.NotContains(t, driftedIDs, account1.ID)
+	require.Contains(t, driftedIDs, account2.ID)
+	require.Equal(t, int64(17), driftedIDs[account2.ID].EntriesTotal)
+	require.Contains(t, driftedIDs, drifted.ID)
+
+	orphanIDs := map[int64]bool{}
+	for _, row := range result.OrphanedEntries {
+		orphanIDs[row.ID] = true
+	}
+	require.True(t, orphanIDs[orphan.ID])
+	require.False(t, orphanIDs[balanced.FromEntry.ID])
+
+	transferIDs := map[int64]int64{}
+	for _, row := range result.UnbalancedTransfers {
+		transferIDs[row.Transfer.ID] = row.EntryCount
+	}
+	require.NotContains(t, transferIDs, balanced.Transfer.ID)
+	require.Contains(t, transferIDs, unbalanced.ID)
+	require.Zero(t, transferIDs[unbalanced.ID])
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccountWithBalance(t, 0)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		ToAmount:      90,
+		ExchangeRate:  "0.9",
+	})
+	require.NoError(t, err)
+
+	// 33 of 100 is paid back with 29.7 rounded down, the rest with the remaining 60.3
+	partial, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:   transfer.Transfer.ID,
+		Amount:       33,
+		ExchangeRate: "1.111111111111",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(33), partial.Original.ReversedAmount)
+	require.Equal(t, transfer.Transfer.ID, partial.Transfer.ReversalOf.Int64)
+	require.Equal(t, account2.ID, partial.Transfer.FromAccountID)
+	require.Equal(t, int64(29), partial.Transfer.Amount)
+	require.Equal(t, int64(33), partial.Transfer.ToAmount)
+	require.Equal(t, int64(61), partial.FromAccount.Balance)
+	require.Equal(t, int64(933), partial.ToAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:   transfer.Transfer.ID,
+		Amount:       68,
+		ExchangeRate: "1.111111111111",
+	})
+	require.True(t, errors.Is(err, ErrOverReversal))
+
+	rest, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:   transfer.Transfer.ID,
+		ExchangeRate: "1.111111111111",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), rest.Original.ReversedAmount)
+	require.Equal(t, int64(61), rest.Transfer.Amount)
+	require.Equal(t, int64(67), rest.Transfer.ToAmount)
+	require.Zero(t, rest.FromAccount.Balance)
+	require.Equal(t, int64(1000), rest.ToAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:   transfer.Transfer.ID,
+		ExchangeRate: "1.111111111111",
+	})
+	require.True(t, errors.Is(err, ErrOverReversal))
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:   rest.Transfer.ID,
+		ExchangeRate: "0.9",
+	})
+	require.True(t, errors.Is(err, ErrReversalNotReversible))
+
+	reversals, err := testQueries.ListTransferReversals(context.Background(), sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, reversals, 2)
+}
+
+func TestCreateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashedPassword(util.RandomPassword())
+	require.NoError(t, err)
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomUsername(),
+			HashedPassword: hashedPassword,
+			Email:          util.RandomEmail(),
+			FullName:       util.RandomFullname(),
+		},
+		SecretCode:           util.RandomString(32),
+		VerifyEmailExpiredAt: time.Now().Add(time.Hour),
+	}
+
+	result, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, arg.Email, result.VerifyEmail.Email)
+	require.Equal(t, arg.SecretCode, result.VerifyEmail.SecretCode)
+	require.False(t, result.VerifyEmail.IsUsed)
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	createVerifyEmail := func(expiredAt time.Time) VerifyEmail {
+		verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+			Username:   user.Username,
+			Email:      user.Email,
+			SecretCode: util.RandomString(32),
+			ExpiredAt:  expiredAt,
+		})
+		require.NoError(t, err)
+		return verifyEmail
+	}
+
+	expired := createVerifyEmail(time.Now().Add(-time.Minute))
+	_, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    expired.ID,
+		SecretCode: expired.SecretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+
+	verifyEmail := createVerifyEmail(time.Now().Add(time.Hour))
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: "wrong",
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.True(t, result.VerifyEmail.IsUsed)
+
+	// a code can only be used once
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	sessionID := uuid.New()
+	_, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:           sessionID,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    util.RandomString(10),
+		ClientIp:     "127.0.0.1",
+		ExpiredAt:    time.Now().Add(time.Hour),
+		FamilyID:     sessionID,
+	})
+	require.NoError(t, err)
+
+	createPasswordReset := func() PasswordReset {
+		reset, err := testQueries.CreatePasswordReset(context.Background(), CreatePasswordResetParams{
+			Username:   user.Username,
+			SecretCode: util.RandomString(32),
+			ExpiredAt:  time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		return reset
+	}
+	reset := createPasswordReset()
+	pending := createPasswordReset()
+
+	hashedPassword, err := util.HashedPassword(util.RandomPassword())
+	require.NoError(t, err)
+	arg := ResetPasswordTxParams{
+		ResetID:        reset.ID,
+		SecretCode:     "wrong",
+		HashedPassword: hashedPassword,
+		ChangedAt:      time.Now(),
+	}
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidPasswordReset)
+
+	arg.SecretCode = reset.SecretCode
+	result, err := store.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.WithinDuration(t, arg.ChangedAt, result.User.PasswordChangedAt, time.Millisecond)
+	require.Equal(t, int64(1), result.RevokedSessions)
+
+	session, err := testQueries.GetSession(context.Background(), sessionID)
+	require.NoError(t, err)
+	require.True(t, session.IsBlocked)
+
+	// the code is used up, and so is every other pending code of the user
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidPasswordReset)
+	arg.ResetID, arg.SecretCode = pending.ID, pending.SecretCode
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidPasswordReset)
+}
+
+func TestUpdateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+
+	newFullName := util.RandomFullname()
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			FullName: sql.NullString{String: newFullName, Valid: true},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.EmailChanged)
+	require.Zero(t, result.VerifyEmail.ID)
+	require.Equal(t, newFullName, result.User.FullName)
+	require.Equal(t, user.Email, result.User.Email)
+	require.True(t, result.User.IsEmailVerified)
+
+	newEmail := util.RandomEmail()
+	secretCode := util.RandomString(32)
+	result, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: newEmail, Valid: true},
+		},
+		SecretCode:           secretCode,
+		VerifyEmailExpiredAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, result.EmailChanged)
+	require.Equal(t, newFullName, result.User.FullName)
+	require.Equal(t, newEmail, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, newEmail, result.VerifyEmail.Email)
+	require.Equal(t, secretCode, result.VerifyEmail.SecretCode)
+
+	// the email of another user cannot be taken
+	other := CreateRandomUser(t)
+	_, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: other.Email, Valid: true},
+		},
+	})
+	require.Error(t, err)
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+}
+
+func TestTOTPTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	_, err := store.ConfirmTOTPTx(context.Background(), ConfirmTOTPTxParams{Username: user.Username, Step: 1})
+	require.ErrorIs(t, err, ErrTOTPNotPending)
+
+	pending, err := testQueries.UpsertPendingUserTotp(context.Background(), UpsertPendingUserTotpParams{
+		Username: user.Username,
+		Secret:   util.RandomString(32),
+	})
+	require.NoError(t, err)
+	require.False(t, pending.ConfirmedAt.Valid)
+
+	hashedCodes := []string{util.RandomString(64), util.RandomString(64)}
+	totp, err := store.ConfirmTOTPTx(context.Background(), ConfirmTOTPTxParams{
+		Username:            user.Username,
+		Step:                100,
+		HashedRecoveryCodes: hashedCodes,
+	})
+	require.NoError(t, err)
+	require.True(t, totp.ConfirmedAt.Valid)
+	require.Equal(t, int64(100), totp.LastUsedStep)
+
+	// enrolling again cannot replace the secret of enabled two-factor authentication
+	_, err = testQueries.UpsertPendingUserTotp(context.Background(), UpsertPendingUserTotpParams{
+		Username: user.Username,
+		Secret:   util.RandomString(32),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// a step is accepted once, and never after a later one
+	_, err = testQueries.UseUserTotpStep(context.Background(), UseUserTotpStepParams{Username: user.Username, Step: 100})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.UseUserTotpStep(context.Background(), UseUserTotpStepParams{Username: user.Username, Step: 101})
+	require.NoError(t, err)
+	_, err = testQueries.UseUserTotpStep(context.Background(), UseUserTotpStepParams{Username: user.Username, Step: 101})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	arg := UseTotpRecoveryCodeParams{Username: user.Username, HashedCode: hashedCodes[0]}
+	_, err = testQueries.UseTotpRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = testQueries.UseTotpRecoveryCode(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = store.DisableTOTPTx(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetUserTotp(context.Background(), user.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.UseTotpRecoveryCode(context.Background(), UseTotpRecoveryCodeParams{Username: user.Username, HashedCode: hashedCodes[1]})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
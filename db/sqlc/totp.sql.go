@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: totp.sql
+
+package db
+
+import (
+	"context"
+)
+
+const confirmUserTotp = `-- name: ConfirmUserTotp :one
+
+UPDATE user_totps SET confirmed_at = now(), last_used_step = $1
+WHERE username = $2 AND confirmed_at IS NULL
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type ConfirmUserTotpParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) ConfirmUserTotp(ctx context.Context, arg ConfirmUserTotpParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, confirmUserTotp, arg.Step, arg.Username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTotpRecoveryCode = `-- name: CreateTotpRecoveryCode :one
+
+INSERT INTO totp_recovery_codes (username, hashed_code) VALUES ($1, $2) RETURNING id, username, hashed_code, used_at, created_at
+`
+
+type CreateTotpRecoveryCodeParams struct {
+	Username   string `json:"username"`
+	HashedCode string `json:"hashed_code"`
+}
+
+func (q *Queries) CreateTotpRecoveryCode(ctx context.Context, arg CreateTotpRecoveryCodeParams) (TotpRecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, createTotpRecoveryCode, arg.Username, arg.HashedCode)
+	var i TotpRecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedCode,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTotpRecoveryCodes = `-- name: DeleteTotpRecoveryCodes :exec
+
+DELETE FROM totp_recovery_codes WHERE username = $1
+`
+
+func (q *Queries) DeleteTotpRecoveryCodes(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, deleteTotpRecoveryCodes, username)
+	return err
+}
+
+const deleteUserTotp = `-- name: DeleteUserTotp :exec
+
+DELETE FROM user_totps WHERE username = $1
+`
+
+func (q *Queries) DeleteUserTotp(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTotp, username)
+	return err
+}
+
+const getUserTotp = `-- name: GetUserTotp :one
+
+SELECT username, secret, confirmed_at, last_used_step, created_at FROM user_totps WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserTotp(ctx context.Context, username string) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, getUserTotp, username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertPendingUserTotp = `-- name: UpsertPendingUserTotp :one
+
+INSERT INTO user_totps (username, secret) VALUES ($1, $2)
+ON CONFLICT (username) DO UPDATE
+  SET secret = EXCLUDED.secret, last_used_step = 0, created_at = now()
+  WHERE user_totps.confirmed_at IS NULL
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type UpsertPendingUserTotpParams struct {
+	Username string `json:"username"`
+	Secret   string `json:"secret"`
+}
+
+// starts or restarts an enrollment, no row means two-factor authentication is already enabled
+func (q *Queries) UpsertPendingUserTotp(ctx context.Context, arg UpsertPendingUserTotpParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, upsertPendingUserTotp, arg.Username, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useTotpRecoveryCode = `-- name: UseTotpRecoveryCode :one
+
+UPDATE totp_recovery_codes SET used_at = now()
+WHERE username = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING id, username, hashed_code, used_at, created_at
+`
+
+type UseTotpRecoveryCodeParams struct {
+	Username   string `json:"username"`
+	HashedCode string `json:"hashed_code"`
+}
+
+func (q *Queries) UseTotpRecoveryCode(ctx context.Context, arg UseTotpRecoveryCodeParams) (TotpRecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, useTotpRecoveryCode, arg.Username, arg.HashedCode)
+	var i TotpRecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedCode,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useUserTotpStep = `-- name: UseUserTotpStep :one
+
+UPDATE user_totps SET last_used_step = $1
+WHERE username = $2 AND confirmed_at IS NOT NULL AND last_used_step < $1
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type UseUserTotpStepParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+// accepts a code once, no row means a code of this step or a later one was already used
+func (q *Queries) UseUserTotpStep(ctx context.Context, arg UseUserTotpStepParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, useUserTotpStep, arg.Step, arg.Username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
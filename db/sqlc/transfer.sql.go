@@ -7,23 +7,62 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+
+UPDATE transfers SET reversed_amount = reversed_amount + $1
+WHERE id = $2 RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 
 INSERT INTO
-    transfers (from_account_id , to_account_id , amount)
-VALUES ($1, $2, $3) RETURNING id, from_account_id, to_account_id, amount, created_at
+    transfers (from_account_id , to_account_id , amount, to_amount, exchange_rate, reversal_of)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        int64         `json:"amount"`
+	ToAmount      int64         `json:"to_amount"`
+	ExchangeRate  string        `json:"exchange_rate"`
+	ReversalOf    sql.NullInt64 `json:"reversal_of"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ReversalOf,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -31,6 +70,10 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
 	)
 	return i, err
 }
@@ -47,7 +90,7 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 
 const getTransfer = `-- name: GetTransfer :one
 
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers  WHERE id = $1 LIMIT 1
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount FROM transfers  WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
@@ -59,13 +102,77 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount FROM transfers WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
 	)
 	return i, err
 }
 
+const listTransferReversals = `-- name: ListTransferReversals :many
+
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount FROM transfers WHERE reversal_of = $1 ORDER BY id
+`
+
+func (q *Queries) ListTransferReversals(ctx context.Context, reversalOf sql.NullInt64) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferReversals, reversalOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
 
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers 
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount FROM transfers 
 WHERE from_account_id = $1 OR to_account_id = $2
 ORDER BY id 
 LIMIT $3 
@@ -99,6 +206,114 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfers = `-- name: SearchTransfers :many
+
+SELECT transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.to_amount, transfers.exchange_rate, transfers.reversal_of, transfers.reversed_amount, from_account.currency AS from_currency, to_account.currency AS to_currency
+FROM transfers
+JOIN accounts from_account ON from_account.id = transfers.from_account_id
+JOIN accounts to_account ON to_account.id = transfers.to_account_id
+WHERE (transfers.from_account_id = $1 OR transfers.to_account_id = $1)
+  AND ($2::timestamptz IS NULL OR transfers.created_at >= $2)
+  AND ($3::timestamptz IS NULL OR transfers.created_at < $3)
+  AND ($4::bigint IS NULL OR CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+  END >= $4)
+  AND ($5::bigint IS NULL OR CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+  END <= $5)
+  AND ($6::bigint IS NULL OR CASE
+    WHEN $7::text = 'amount' AND NOT $8::bool THEN (CASE
+      WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+    END, transfers.id) > ($9::bigint, $6)
+    WHEN $7::text = 'amount' THEN (CASE
+      WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+    END, transfers.id) < ($9::bigint, $6)
+    WHEN NOT $8::bool THEN transfers.id > $6
+    ELSE transfers.id < $6
+  END)
+ORDER BY
+  CASE WHEN $7::text = 'amount' AND NOT $8::bool THEN CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+  END END ASC,
+  CASE WHEN $7::text = 'amount' AND $8::bool THEN CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.amount ELSE transfers.to_amount
+  END END DESC,
+  CASE WHEN NOT $8::bool THEN transfers.id END ASC,
+  CASE WHEN $8::bool THEN transfers.id END DESC
+LIMIT $10
+`
+
+type SearchTransfersParams struct {
+	AccountID   int64         `json:"account_id"`
+	FromTime    sql.NullTime  `json:"from_time"`
+	ToTime      sql.NullTime  `json:"to_time"`
+	MinAmount   sql.NullInt64 `json:"min_amount"`
+	MaxAmount   sql.NullInt64 `json:"max_amount"`
+	AfterID     sql.NullInt64 `json:"after_id"`
+	OrderBy     string        `json:"order_by"`
+	Descending  bool          `json:"descending"`
+	AfterAmount int64         `json:"after_amount"`
+	Limit       int32         `json:"limit"`
+}
+
+type SearchTransfersRow struct {
+	Transfer     Transfer `json:"transfer"`
+	FromCurrency string   `json:"from_currency"`
+	ToCurrency   string   `json:"to_currency"`
+}
+
+// amounts are compared in the currency of the given account:
+// what it sent for outgoing transfers, what it received for incoming ones
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.AfterID,
+		arg.OrderBy,
+		arg.Descending,
+		arg.AfterAmount,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTransfersRow{}
+	for rows.Next() {
+		var i SearchTransfersRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.ToAmount,
+			&i.Transfer.ExchangeRate,
+			&i.Transfer.ReversalOf,
+			&i.Transfer.ReversedAmount,
+			&i.FromCurrency,
+			&i.ToCurrency,
 		); err != nil {
 			return nil, err
 		}
@@ -115,7 +330,7 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 
 const updateTransfer = `-- name: UpdateTransfer :one
 
-UPDATE transfers  set amount = $2 WHERE id = $1 RETURNING id, from_account_id, to_account_id, amount, created_at
+UPDATE transfers  set amount = $2 WHERE id = $1 RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount
 `
 
 type UpdateTransferParams struct {
@@ -132,6 +347,10 @@ func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
 	)
 	return i, err
 }
@@ -11,10 +11,13 @@ import (
 )
 
 func createRandomTransfer(t *testing.T, from_account Account, to_account Account) Transfer {
+	amount := util.RandomMoney()
 	arg := CreateTransferParams{
 		FromAccountID: from_account.ID,
 		ToAccountID:   to_account.ID,
-		Amount:        util.RandomMoney(),
+		Amount:        amount,
+		ToAmount:      amount,
+		ExchangeRate:  "1",
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -115,3 +118,48 @@ func TestListTransfers(t *testing.T) {
 		require.Equal(t, transfer.ToAccountID, toAccount.ID)
 	}
 }
+
+func TestSearchTransfers(t *testing.T) {
+	account := CreateRandomAccount(t)
+	otherAccount := CreateRandomAccount(t)
+
+	// outgoing transfers are compared by the amount sent, incoming ones by the amount received
+	outgoing, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account.ID,
+		ToAccountID:   otherAccount.ID,
+		Amount:        500,
+		ToAmount:      20,
+		ExchangeRate:  "0.04",
+	})
+	require.NoError(t, err)
+	incoming, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: otherAccount.ID,
+		ToAccountID:   account.ID,
+		Amount:        20,
+		ToAmount:      500,
+		ExchangeRate:  "25",
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID:  account.ID,
+		MinAmount:  sql.NullInt64{Int64: 100, Valid: true},
+		OrderBy:    "created_at",
+		Descending: true,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, incoming.ID, rows[0].Transfer.ID)
+	require.Equal(t, outgoing.ID, rows[1].Transfer.ID)
+	require.Equal(t, account.Currency, rows[1].FromCurrency)
+	require.Equal(t, otherAccount.Currency, rows[1].ToCurrency)
+
+	rows, err = testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: otherAccount.ID,
+		MinAmount: sql.NullInt64{Int64: 100, Valid: true},
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// SuspenseAccountOwner owns the bank's suspense accounts, one per currency
+const SuspenseAccountOwner = "simplebank_suspense"
+
+type AdjustBalanceTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Amount is credited to the account when positive and debited when negative
+	Amount   int64  `json:"amount"`
+	Reason   string `json:"reason"`
+	Operator string `json:"operator"`
+}
+
+type AdjustBalanceTxResult struct {
+	Adjustment      BalanceAdjustment `json:"adjustment"`
+	Account         Account           `json:"account"`
+	SuspenseAccount Account           `json:"suspense_account"`
+	Entry           Entry             `json:"entry"`
+	SuspenseEntry   Entry             `json:"suspense_entry"`
+}
+
+// AdjustBalanceTx changes the balance of an account and books the opposite amount
+// on the suspense account of the same currency, so that the ledger stays balanced.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateSuspenseAccount(ctx, CreateSuspenseAccountParams{
+			Owner:    SuspenseAccountOwner,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		suspenseAccount, err := q.GetAccountByOwnerAndCurrency(ctx, GetAccountByOwnerAndCurrencyParams{
+			Owner:    SuspenseAccountOwner,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+		if suspenseAccount.ID == account.ID {
+			return fmt.Errorf("cannot adjust suspense account [%d]", account.ID)
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.SuspenseEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: suspenseAccount.ID,
+			Amount:    -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if account.ID > suspenseAccount.ID {
+			result.Account, result.SuspenseAccount, err = AddMoney(ctx, q, account.ID, arg.Amount, suspenseAccount.ID, -arg.Amount)
+		} else {
+			result.SuspenseAccount, result.Account, err = AddMoney(ctx, q, suspenseAccount.ID, -arg.Amount, account.ID, arg.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		// checked once the row is locked: frozen accounts can still be corrected, closed ones must stay at zero
+		if result.Account.Status == AccountStatusClosed {
+			return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, result.Account.Status)
+		}
+
+		// the suspense account may go negative, customer accounts keep their overdraft limit
+		if arg.Amount < 0 && result.Account.Balance < -result.Account.OverdraftLimit {
+			return fmt.Errorf("%w: account [%d] balance %d is below overdraft limit %d",
+				ErrInsufficientFunds, account.ID, result.Account.Balance, result.Account.OverdraftLimit)
+		}
+
+		result.Adjustment, err = q.CreateBalanceAdjustment(ctx, CreateBalanceAdjustmentParams{
+			AccountID:         account.ID,
+			SuspenseAccountID: suspenseAccount.ID,
+			Amount:            arg.Amount,
+			Reason:            arg.Reason,
+			Operator:          arg.Operator,
+			EntryID:           result.Entry.ID,
+			SuspenseEntryID:   result.SuspenseEntry.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
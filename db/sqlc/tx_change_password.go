@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrInvalidPasswordReset is returned for a password reset code that is unknown, already used or expired
+var ErrInvalidPasswordReset = errors.New("invalid or expired password reset code")
+
+type ChangePasswordTxParams struct {
+	Username       string `json:"username"`
+	HashedPassword string `json:"hashed_password"`
+	// ChangedAt is compared with the issue time of access tokens, so it comes from the same clock
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type ChangePasswordTxResult struct {
+	User            User  `json:"user"`
+	RevokedSessions int64 `json:"revoked_sessions"`
+}
+
+// ChangePasswordTx replaces the password of a user and signs them out everywhere: their
+// sessions are blocked and pending reset codes can no longer be used
+func (store *SQLStore) ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error) {
+	var result ChangePasswordTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		result, err = changePassword(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+type ResetPasswordTxParams struct {
+	ResetID        int64     `json:"reset_id"`
+	SecretCode     string    `json:"secret_code"`
+	HashedPassword string    `json:"hashed_password"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+// ResetPasswordTx uses up a password reset code and changes the password of its user
+// like ChangePasswordTx does
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ChangePasswordTxResult, error) {
+	var result ChangePasswordTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		reset, err := q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:         arg.ResetID,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidPasswordReset
+			}
+			return err
+		}
+
+		result, err = changePassword(ctx, q, ChangePasswordTxParams{
+			Username:       reset.Username,
+			HashedPassword: arg.HashedPassword,
+			ChangedAt:      arg.ChangedAt,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func changePassword(ctx context.Context, q *Queries, arg ChangePasswordTxParams) (ChangePasswordTxResult, error) {
+	var result ChangePasswordTxResult
+	var err error
+
+	result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+		Username:          arg.Username,
+		HashedPassword:    arg.HashedPassword,
+		PasswordChangedAt: arg.ChangedAt,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.RevokedSessions, err = q.BlockUserSessions(ctx, arg.Username)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = q.ExpireUserPasswordResets(ctx, arg.Username)
+	return result, err
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type CreateUserTxParams struct {
+	CreateUserParams
+	// SecretCode is sent to the user to verify their email, it expires at VerifyEmailExpiredAt
+	SecretCode           string
+	VerifyEmailExpiredAt time.Time
+}
+
+type CreateUserTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verify_email"`
+}
+
+// CreateUserTx creates a user together with the code that verifies their email. The caller
+// mails the code once the transaction is committed, since execTx may retry the transaction.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:   result.User.Username,
+			Email:      result.User.Email,
+			SecretCode: arg.SecretCode,
+			ExpiredAt:  arg.VerifyEmailExpiredAt,
+		})
+		return err
+	})
+
+	return result, err
+}
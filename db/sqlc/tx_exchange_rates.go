@@ -0,0 +1,22 @@
+package db
+
+import "context"
+
+// CreateExchangeRatesTx inserts a batch of exchange rates, either all of them or none
+func (store *SQLStore) CreateExchangeRatesTx(ctx context.Context, arg []CreateExchangeRateParams) ([]ExchangeRate, error) {
+	rates := make([]ExchangeRate, 0, len(arg))
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		rates = rates[:0]
+		for _, params := range arg {
+			rate, err := q.CreateExchangeRate(ctx, params)
+			if err != nil {
+				return err
+			}
+			rates = append(rates, rate)
+		}
+		return nil
+	})
+
+	return rates, err
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+type ReconcileTxResult struct {
+	Counts              CountLedgerRow               `json:"counts"`
+	BalanceDrifts       []ListBalanceDriftsRow       `json:"balance_drifts"`
+	OrphanedEntries     []ListOrphanedEntriesRow     `json:"orphaned_entries"`
+	UnbalancedTransfers []ListUnbalancedTransfersRow `json:"unbalanced_transfers"`
+}
+
+// ReconcileTx scans the whole ledger for inconsistencies. The checks run on one snapshot so
+// that transfers committed while scanning cannot show up as drift.
+func (store *SQLStore) ReconcileTx(ctx context.Context) (ReconcileTxResult, error) {
+	var result ReconcileTxResult
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTx(ctx, opts, func(q *Queries) error {
+		var err error
+
+		result.Counts, err = q.CountLedger(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.BalanceDrifts, err = q.ListBalanceDrifts(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.OrphanedEntries, err = q.ListOrphanedEntries(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.UnbalancedTransfers, err = q.ListUnbalancedTransfers(ctx)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrOverReversal is returned when a reversal would pay back more than what is left of a transfer
+var ErrOverReversal = errors.New("reversal exceeds the amount left to reverse")
+
+// ErrReversalNotReversible is returned when reversing a transfer that is itself a reversal
+var ErrReversalNotReversible = errors.New("a reversal cannot be reversed")
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is paid back to the sender of the transfer, in the currency of its from account.
+	// Zero reverses everything that is left.
+	Amount int64 `json:"amount"`
+	// ExchangeRate is the rate of the reversal, the inverse of the rate of the transfer
+	ExchangeRate string `json:"exchange_rate"`
+}
+
+type ReverseTransferTxResult struct {
+	// Original is the reversed transfer with its updated reversed amount
+	Original Transfer `json:"original"`
+	TransferTxResult
+}
+
+// ReverseTransferTx books a compensating transfer from the recipient back to the sender.
+// Cross-currency transfers are reversed at their original rate, and the debit of the
+// recipient is derived from the total reversed so far, so that reversing a transfer in
+// several parts ends up debiting exactly its to_amount.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the row lock serializes reversals of the same transfer
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if original.ReversalOf.Valid {
+			return fmt.Errorf("%w: transfer [%d] reverses transfer [%d]", ErrReversalNotReversible, original.ID, original.ReversalOf.Int64)
+		}
+
+		remaining := original.Amount - original.ReversedAmount
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount <= 0 || amount > remaining {
+			return fmt.Errorf("%w: transfer [%d] has %d left to reverse, got %d", ErrOverReversal, original.ID, remaining, amount)
+		}
+
+		debit := reversalDebit(original, original.ReversedAmount+amount) - reversalDebit(original, original.ReversedAmount)
+		if debit <= 0 {
+			return fmt.Errorf("reversal of %d is too small to be converted at the rate of transfer [%d]", amount, original.ID)
+		}
+
+		result.Original, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        debit,
+			ToAmount:      amount,
+			ExchangeRate:  arg.ExchangeRate,
+		}, sql.NullInt64{Int64: original.ID, Valid: true})
+		return err
+	})
+
+	return result, err
+}
+
+// reversalDebit is the part of to_amount taken back from the recipient once reversed of
+// amount has been paid back to the sender, rounded down in favour of the recipient
+func reversalDebit(original Transfer, reversed int64) int64 {
+	if original.Amount == original.ToAmount {
+		return reversed
+	}
+	// the product can overflow int64, the quotient is at most to_amount
+	debit := new(big.Int).Mul(big.NewInt(reversed), big.NewInt(original.ToAmount))
+	return debit.Quo(debit, big.NewInt(original.Amount)).Int64()
+}
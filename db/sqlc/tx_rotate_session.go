@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenReused is returned when a refresh token that was already exchanged is presented again
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+type RotateSessionTxParams struct {
+	ParentID uuid.UUID `json:"parent_id"`
+	// FamilyID and ParentID of the new session are taken from the parent
+	NewSession CreateSessionParams `json:"new_session"`
+}
+
+// RotateSessionTx marks the parent session as rotated and creates its successor in the same family.
+// Only one rotation of a session can ever succeed, the others get ErrRefreshTokenReused.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error) {
+	var session Session
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		parent, err := q.RotateSession(ctx, arg.ParentID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrRefreshTokenReused
+			}
+			return err
+		}
+
+		newSession := arg.NewSession
+		newSession.FamilyID = parent.FamilyID
+		newSession.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+
+		session, err = q.CreateSession(ctx, newSession)
+		return err
+	})
+
+	return session, err
+}
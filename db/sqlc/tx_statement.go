@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type StatementTxParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type StatementTxResult struct {
+	Account Account `json:"account"`
+	// balances at the start and at the end of the period
+	OpeningBalance int64                     `json:"opening_balance"`
+	ClosingBalance int64                     `json:"closing_balance"`
+	Entries        []ListStatementEntriesRow `json:"entries"`
+}
+
+// StatementTx reads the entries of an account over [FromTime, ToTime) together with the
+// balances around them. Balances are derived backwards from accounts.balance, and everything
+// is read from the same snapshot, so the closing balance always reconciles with the account.
+func (store *SQLStore) StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error) {
+	var result StatementTxResult
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTx(ctx, opts, func(q *Queries) error {
+		var err error
+
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		sinceFrom, err := q.SumEntriesSince(ctx, SumEntriesSinceParams{
+			AccountID: arg.AccountID,
+			CreatedAt: arg.FromTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		sinceTo, err := q.SumEntriesSince(ctx, SumEntriesSinceParams{
+			AccountID: arg.AccountID,
+			CreatedAt: arg.ToTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.OpeningBalance = result.Account.Balance - sinceFrom
+		result.ClosingBalance = result.Account.Balance - sinceTo
+
+		result.Entries, err = q.ListStatementEntries(ctx, ListStatementEntriesParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.FromTime,
+			ToTime:    arg.ToTime,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrTOTPNotPending is returned when confirming two-factor authentication that was never
+// enrolled or is already enabled
+var ErrTOTPNotPending = errors.New("no pending two-factor enrollment")
+
+type ConfirmTOTPTxParams struct {
+	Username string `json:"username"`
+	// Step is the time step of the code that confirmed the secret
+	Step int64 `json:"step"`
+	// HashedRecoveryCodes replace any recovery code the user had before
+	HashedRecoveryCodes []string `json:"hashed_recovery_codes"`
+}
+
+// ConfirmTOTPTx enables the pending two-factor authentication of a user together with their recovery codes
+func (store *SQLStore) ConfirmTOTPTx(ctx context.Context, arg ConfirmTOTPTxParams) (UserTotp, error) {
+	var result UserTotp
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+
+		result, err = q.ConfirmUserTotp(ctx, ConfirmUserTotpParams{
+			Username: arg.Username,
+			Step:     arg.Step,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTOTPNotPending
+			}
+			return err
+		}
+
+		if err := q.DeleteTotpRecoveryCodes(ctx, arg.Username); err != nil {
+			return err
+		}
+		for _, hashedCode := range arg.HashedRecoveryCodes {
+			_, err = q.CreateTotpRecoveryCode(ctx, CreateTotpRecoveryCodeParams{
+				Username:   arg.Username,
+				HashedCode: hashedCode,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// DisableTOTPTx removes the two-factor secret and the recovery codes of a user
+func (store *SQLStore) DisableTOTPTx(ctx context.Context, username string) error {
+	return store.execTx(ctx, nil, func(q *Queries) error {
+		if err := q.DeleteTotpRecoveryCodes(ctx, username); err != nil {
+			return err
+		}
+		return q.DeleteUserTotp(ctx, username)
+	})
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// SecretCode verifies the new email when the email changes, it expires at VerifyEmailExpiredAt
+	SecretCode           string
+	VerifyEmailExpiredAt time.Time
+}
+
+type UpdateUserTxResult struct {
+	User         User        `json:"user"`
+	EmailChanged bool        `json:"email_changed"`
+	VerifyEmail  VerifyEmail `json:"verify_email"`
+}
+
+// UpdateUserTx updates the profile of a user. A new email has to be verified again, so a
+// verification code is created for it. The caller mails the code once the transaction is
+// committed, since execTx may retry the transaction.
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		old, err := q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+
+		result.EmailChanged = result.User.Email != old.Email
+		if !result.EmailChanged {
+			return nil
+		}
+
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:   result.User.Username,
+			Email:      result.User.Email,
+			SecretCode: arg.SecretCode,
+			ExpiredAt:  arg.VerifyEmailExpiredAt,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrInvalidVerifyEmail is returned for a verification code that is unknown, already used or expired,
+// or that was sent to an address the user no longer has
+var ErrInvalidVerifyEmail = errors.New("invalid or expired email verification code")
+
+type VerifyEmailTxParams struct {
+	EmailID    int64  `json:"email_id"`
+	SecretCode string `json:"secret_code"`
+}
+
+type VerifyEmailTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verify_email"`
+}
+
+// VerifyEmailTx uses up a verification code and marks the email of its user as verified
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+
+		result.VerifyEmail, err = q.UseVerifyEmail(ctx, UseVerifyEmailParams{
+			ID:         arg.EmailID,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidVerifyEmail
+			}
+			return err
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			Username: result.VerifyEmail.Username,
+			Email:    result.VerifyEmail.Email,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidVerifyEmail
+		}
+		return err
+	})
+
+	return result, err
+}
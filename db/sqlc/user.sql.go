@@ -7,13 +7,15 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
 
 INSERT INTO
     users (username, hashed_password, full_name, email)
-VALUES ($1, $2, $3, $4) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+VALUES ($1, $2, $3, $4) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified
 `
 
 type CreateUserParams struct {
@@ -38,13 +40,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
 
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users WHERE username = $1 LIMIT 1
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified FROM users WHERE username = $1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -57,6 +61,158 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified FROM users WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const getUserPasswordChangedAt = `-- name: GetUserPasswordChangedAt :one
+
+SELECT password_changed_at FROM users WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getUserPasswordChangedAt, username)
+	var password_changed_at time.Time
+	err := row.Scan(&password_changed_at)
+	return password_changed_at, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+
+UPDATE users SET
+  full_name = COALESCE($1, full_name),
+  email = COALESCE($2, email),
+  is_email_verified = CASE
+    WHEN $2::varchar IS NULL OR $2 = email THEN is_email_verified
+    ELSE false
+  END
+WHERE username = $3
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified
+`
+
+type UpdateUserParams struct {
+	FullName sql.NullString `json:"full_name"`
+	Email    sql.NullString `json:"email"`
+	Username string         `json:"username"`
+}
+
+// only the given fields change, a new email is not verified anymore
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser, arg.FullName, arg.Email, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+
+UPDATE users SET hashed_password = $2, password_changed_at = $3
+WHERE username = $1 RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified
+`
+
+type UpdateUserPasswordParams struct {
+	Username          string    `json:"username"`
+	HashedPassword    string    `json:"hashed_password"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.Username, arg.HashedPassword, arg.PasswordChangedAt)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+
+UPDATE users SET role = $2 WHERE username = $1 RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified
+`
+
+type UpdateUserRoleParams struct {
+	Username string   `json:"username"`
+	Role     UserRole `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+
+UPDATE users SET is_email_verified = TRUE
+WHERE username = $1 AND email = $2 RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, is_email_verified
+`
+
+type VerifyUserEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
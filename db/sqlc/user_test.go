@@ -36,6 +36,8 @@ func CreateRandomUser(t *testing.T) User {
 
 	require.NotZero(t, user.CreatedAt)
 	require.True(t, user.PasswordChangedAt.IsZero())
+	require.Equal(t, UserRoleDepositor, user.Role)
+	require.False(t, user.IsEmailVerified)
 	return user
 }
 
@@ -53,3 +55,15 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.FullName, user2.FullName)
 
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := CreateRandomUser(t)
+
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     UserRoleBanker,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, UserRoleBanker, user2.Role)
+}
@@ -0,0 +1,76 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: verify_email.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createVerifyEmail = `-- name: CreateVerifyEmail :one
+
+INSERT INTO
+    verify_emails (username, email, secret_code, expired_at)
+VALUES ($1, $2, $3, $4) RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+`
+
+type CreateVerifyEmailParams struct {
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	SecretCode string    `json:"secret_code"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
+	row := q.db.QueryRowContext(ctx, createVerifyEmail,
+		arg.Username,
+		arg.Email,
+		arg.SecretCode,
+		arg.ExpiredAt,
+	)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const useVerifyEmail = `-- name: UseVerifyEmail :one
+
+UPDATE verify_emails SET is_used = TRUE
+WHERE id = $1
+  AND secret_code = $2
+  AND is_used = FALSE
+  AND expired_at > now()
+RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+`
+
+type UseVerifyEmailParams struct {
+	ID         int64  `json:"id"`
+	SecretCode string `json:"secret_code"`
+}
+
+// marks an unused and unexpired code as used, no row means the code is not valid
+func (q *Queries) UseVerifyEmail(ctx context.Context, arg UseVerifyEmailParams) (VerifyEmail, error) {
+	row := q.db.QueryRowContext(ctx, useVerifyEmail, arg.ID, arg.SecretCode)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
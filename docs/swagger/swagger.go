@@ -0,0 +1,7 @@
+// Package swagger embeds the OpenAPI document generated from the proto definitions
+package swagger
+
+import "embed"
+
+//go:embed *.swagger.json
+var FS embed.FS
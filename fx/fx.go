@@ -0,0 +1,181 @@
+// Package fx looks up exchange rates and converts amounts between currencies
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+// rateDecimals is the precision used when an applied rate has to be rounded, e.g. an inverted rate
+const rateDecimals = 12
+
+var (
+	ErrRateNotFound   = errors.New("exchange rate not found")
+	ErrAmountTooSmall = errors.New("converted amount is too small")
+	ErrAmountTooLarge = errors.New("converted amount is too large")
+)
+
+// Conversion describes an amount converted from one currency to another
+type Conversion struct {
+	FromCurrency    string       `json:"from_currency"`
+	ToCurrency      string       `json:"to_currency"`
+	Amount          money.Amount `json:"amount"`
+	ConvertedAmount money.Amount `json:"converted_amount"`
+	Rate            string       `json:"rate"`
+}
+
+// Service looks up exchange rates stored in the database
+type Service struct {
+	store db.Store
+}
+
+// NewService creates a new exchange rate service
+func NewService(store db.Store) *Service {
+	return &Service{store: store}
+}
+
+// Rate returns how much of the quote currency one unit of the base currency buys at the given time.
+// When only the opposite pair is known, its inverse is used.
+func (service *Service) Rate(ctx context.Context, base string, quote string, at time.Time) (*big.Rat, error) {
+	if base == quote {
+		return big.NewRat(1, 1), nil
+	}
+
+	rate, err := service.lookup(ctx, base, quote, at)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, ErrRateNotFound) {
+		return nil, err
+	}
+
+	inverse, err := service.lookup(ctx, quote, base, at)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err = ParseRate(inverse.Inv(inverse).FloatString(rateDecimals))
+	if err != nil {
+		return nil, fmt.Errorf("cannot invert %s/%s rate: %w", quote, base, err)
+	}
+	return rate, nil
+}
+
+// Convert converts an amount into the to currency using the current rate.
+// Rates are quoted per major unit, so the minor units of both currencies are taken into account.
+// The converted amount is rounded down.
+func (service *Service) Convert(ctx context.Context, amount money.Amount, to string) (Conversion, error) {
+	from := amount.Currency()
+	conversion := Conversion{
+		FromCurrency: from.Code,
+		ToCurrency:   to,
+		Amount:       amount,
+	}
+
+	toCurrency, err := money.Lookup(to)
+	if err != nil {
+		return conversion, err
+	}
+
+	rate, err := service.Rate(ctx, from.Code, to, time.Now())
+	if err != nil {
+		return conversion, err
+	}
+
+	numerator := new(big.Int).Mul(big.NewInt(amount.Units()), rate.Num())
+	numerator.Mul(numerator, pow10(toCurrency.Exponent))
+	denominator := new(big.Int).Mul(rate.Denom(), pow10(from.Exponent))
+
+	converted := numerator.Quo(numerator, denominator)
+	if !converted.IsInt64() {
+		return conversion, ErrAmountTooLarge
+	}
+	if converted.Sign() <= 0 {
+		return conversion, ErrAmountTooSmall
+	}
+
+	conversion.ConvertedAmount = money.New(converted.Int64(), to)
+	conversion.Rate = FormatRate(rate)
+	return conversion, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// UploadRates validates and stores a batch of exchange rates
+func (service *Service) UploadRates(ctx context.Context, rates []db.CreateExchangeRateParams) ([]db.ExchangeRate, error) {
+	for i, rate := range rates {
+		if !money.IsSupported(rate.BaseCurrency) || !money.IsSupported(rate.QuoteCurrency) {
+			return nil, fmt.Errorf("rate #%d: unsupported currency pair %s/%s", i, rate.BaseCurrency, rate.QuoteCurrency)
+		}
+		if rate.BaseCurrency == rate.QuoteCurrency {
+			return nil, fmt.Errorf("rate #%d: base and quote currency must differ", i)
+		}
+		if _, err := ParseRate(rate.Rate); err != nil {
+			return nil, fmt.Errorf("rate #%d: %w", i, err)
+		}
+	}
+
+	return service.store.CreateExchangeRatesTx(ctx, rates)
+}
+
+func (service *Service) lookup(ctx context.Context, base string, quote string, at time.Time) (*big.Rat, error) {
+	exchangeRate, err := service.store.GetExchangeRate(ctx, db.GetExchangeRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		At:            at,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+		}
+		return nil, err
+	}
+
+	return ParseRate(exchangeRate.Rate)
+}
+
+// ParseRate parses a positive decimal exchange rate such as "24350.5"
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return nil, fmt.Errorf("invalid rate %q: must be a decimal number", s)
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q: must be positive", s)
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > rateDecimals {
+		return nil, fmt.Errorf("invalid rate %q: at most %d decimals are allowed", s, rateDecimals)
+	}
+	return rate, nil
+}
+
+// FormatRate formats a rate as a decimal string without trailing zeros
+func FormatRate(rate *big.Rat) string {
+	s := rate.FloatString(rateDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// InverseRate returns the rate of the opposite currency pair, e.g. to pay back a transfer at its original rate
+func InverseRate(s string) (string, error) {
+	rate, err := ParseRate(s)
+	if err != nil {
+		return "", err
+	}
+
+	inverse := FormatRate(rate.Inv(rate))
+	if inverse == "0" {
+		return "", fmt.Errorf("cannot invert rate %q: inverse is below %d decimals", s, rateDecimals)
+	}
+	return inverse, nil
+}
@@ -0,0 +1,161 @@
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		name          string
+		from          string
+		to            string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, conversion Conversion, err error)
+	}{
+		{
+			name:   "SameCurrency",
+			from:   money.USD,
+			to:     money.USD,
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, conversion Conversion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(100), conversion.ConvertedAmount.Units())
+				require.Equal(t, "1", conversion.Rate)
+			},
+		},
+		{
+			name:   "DirectRate",
+			from:   money.USD,
+			to:     money.VND,
+			amount: 305,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.GetExchangeRateParams) (db.ExchangeRate, error) {
+						require.Equal(t, money.USD, arg.BaseCurrency)
+						require.Equal(t, money.VND, arg.QuoteCurrency)
+						return db.ExchangeRate{Rate: "24350.50"}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, conversion Conversion, err error) {
+				require.NoError(t, err)
+				// 3.05 USD * 24350.5 = 74269.025 VND, which has no minor unit
+				require.Equal(t, int64(74269), conversion.ConvertedAmount.Units())
+				require.Equal(t, "24350.5", conversion.Rate)
+			},
+		},
+		{
+			name:   "InverseRate",
+			from:   money.EUR,
+			to:     money.USD,
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						GetExchangeRate(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ExchangeRate{}, sql.ErrNoRows),
+					store.EXPECT().
+						GetExchangeRate(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ context.Context, arg db.GetExchangeRateParams) (db.ExchangeRate, error) {
+							require.Equal(t, money.USD, arg.BaseCurrency)
+							require.Equal(t, money.EUR, arg.QuoteCurrency)
+							return db.ExchangeRate{Rate: "0.8"}, nil
+						}),
+				)
+			},
+			checkResponse: func(t *testing.T, conversion Conversion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(125), conversion.ConvertedAmount.Units())
+				require.Equal(t, "1.25", conversion.Rate)
+			},
+		},
+		{
+			name:   "RateNotFound",
+			from:   money.EUR,
+			to:     money.VND,
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.ExchangeRate{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, conversion Conversion, err error) {
+				require.True(t, errors.Is(err, ErrRateNotFound))
+			},
+		},
+		{
+			name:   "AmountTooSmall",
+			from:   money.VND,
+			to:     money.USD,
+			amount: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExchangeRate{Rate: "0.000004"}, nil)
+			},
+			checkResponse: func(t *testing.T, conversion Conversion, err error) {
+				require.True(t, errors.Is(err, ErrAmountTooSmall))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			service := NewService(store)
+			conversion, err := service.Convert(context.Background(), money.New(tc.amount, tc.from), tc.to)
+			tc.checkResponse(t, conversion, err)
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("24350.5")
+	require.NoError(t, err)
+	require.Equal(t, "24350.5", FormatRate(rate))
+
+	for _, invalid := range []string{"", "abc", "0", "-1.5", "1/3", "1e5", "0.0000000000001"} {
+		_, err := ParseRate(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestInverseRate(t *testing.T) {
+	rate, err := InverseRate("0.8")
+	require.NoError(t, err)
+	require.Equal(t, "1.25", rate)
+
+	rate, err = InverseRate("0.9")
+	require.NoError(t, err)
+	require.Equal(t, "1.111111111111", rate)
+
+	_, err = InverseRate("10000000000000")
+	require.Error(t, err)
+
+	_, err = InverseRate("abc")
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeader = "authorization"
+	authorizationBearer = "bearer"
+)
+
+// authorizeUser returns the payload stored by the auth interceptor. Requests coming
+// through the in-process HTTP gateway bypass the interceptor chain, so the access
+// token is verified here when the context does not carry a payload yet.
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	if payload, ok := authPayloadFromContext(ctx); ok {
+		return payload, nil
+	}
+	return server.verifyAccessToken(ctx)
+}
+
+// verifyAccessToken verifies the bearer access token carried in the incoming metadata
+func (server *Server) verifyAccessToken(ctx context.Context) (*token.Payload, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("missing metadata")
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("missing authorization header")
+	}
+
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid authorization header format")
+	}
+
+	authType := strings.ToLower(fields[0])
+	if authType != authorizationBearer {
+		return nil, fmt.Errorf("unsupported authorization type: %s", authType)
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+	// refresh tokens and other scoped tokens are not access tokens
+	if err := payload.CheckScope(""); err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+
+	// access tokens issued before a password change are no longer valid
+	passwordChangedAt, err := server.store.GetUserPasswordChangedAt(ctx, payload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid access token: %s", token.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("cannot check access token: %s", err)
+	}
+	if err := payload.CheckPasswordChangedAt(passwordChangedAt); err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+
+	return payload, nil
+}
+
+// staffRoles can view the accounts and transfers of every customer
+var staffRoles = []db.UserRole{db.UserRoleBanker, db.UserRoleAdmin}
+
+type accountAction int
+
+const (
+	accountView accountAction = iota
+	accountManage
+)
+
+// requireRole fails with PermissionDenied unless the payload carries one of the given roles
+func requireRole(payload *token.Payload, roles ...db.UserRole) error {
+	for _, role := range roles {
+		if db.UserRole(payload.Role) == role {
+			return nil
+		}
+	}
+	return permissionDeniedError(fmt.Errorf("role %q is not allowed, requires one of %v", payload.Role, roles))
+}
+
+// getAuthorizedAccount loads an account and makes sure the caller may perform the action on it.
+// Owners can do anything with their accounts, staff can only view the others.
+func (server *Server) getAuthorizedAccount(ctx context.Context, payload *token.Payload, accountID int64, action accountAction) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if account.Owner == payload.Username {
+		return account, nil
+	}
+	if action == accountView && requireRole(payload, staffRoles...) == nil {
+		return account, nil
+	}
+	return account, permissionDeniedError(fmt.Errorf("account doesn't belong to the authenticated user"))
+}
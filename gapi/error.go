@@ -0,0 +1,18 @@
+package gapi
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func invalidArgumentError(field string, err error) error {
+	return status.Errorf(codes.InvalidArgument, "invalid %s: %s", field, err)
+}
+
+func unauthenticatedError(err error) error {
+	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+}
+
+func permissionDeniedError(err error) error {
+	return status.Errorf(codes.PermissionDenied, "%s", err)
+}
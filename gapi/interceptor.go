@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"google.golang.org/grpc"
+)
+
+type accessLevel int
+
+const (
+	accessAuthenticated accessLevel = iota
+	accessPublic
+)
+
+// methodAccess lists the access level required by every SimpleBank RPC.
+// Methods missing from this list require authentication.
+var methodAccess = map[string]accessLevel{
+	"/pb.SimpleBank/CreateUser":     accessPublic,
+	"/pb.SimpleBank/LoginUser":      accessPublic,
+	"/pb.SimpleBank/LoginUserMFA":   accessPublic,
+	"/pb.SimpleBank/UpdateUser":     accessAuthenticated,
+	"/pb.SimpleBank/CreateAccount":  accessAuthenticated,
+	"/pb.SimpleBank/GetAccount":     accessAuthenticated,
+	"/pb.SimpleBank/ListAccounts":   accessAuthenticated,
+	"/pb.SimpleBank/CloseAccount":   accessAuthenticated,
+	"/pb.SimpleBank/ListEntries":    accessAuthenticated,
+	"/pb.SimpleBank/CreateTransfer": accessAuthenticated,
+}
+
+type authPayloadContextKey struct{}
+
+func contextWithAuthPayload(ctx context.Context, payload *token.Payload) context.Context {
+	return context.WithValue(ctx, authPayloadContextKey{}, payload)
+}
+
+func authPayloadFromContext(ctx context.Context) (*token.Payload, bool) {
+	payload, ok := ctx.Value(authPayloadContextKey{}).(*token.Payload)
+	return payload, ok
+}
+
+// authenticate verifies the access token of non-public methods and stores its payload in the context
+func (server *Server) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if methodAccess[fullMethod] == accessPublic {
+		return ctx, nil
+	}
+
+	payload, err := server.verifyAccessToken(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	return contextWithAuthPayload(ctx, payload), nil
+}
+
+// UnaryAuthInterceptor authenticates unary RPCs
+func (server *Server) UnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := server.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates streaming RPCs
+func (server *Server) StreamAuthInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := server.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream overrides the stream context with one carrying the auth payload
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
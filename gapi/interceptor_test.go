@@ -0,0 +1,182 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodAccessCoversService(t *testing.T) {
+	for _, method := range pb.SimpleBank_ServiceDesc.Methods {
+		fullMethod := "/" + pb.SimpleBank_ServiceDesc.ServiceName + "/" + method.MethodName
+		_, ok := methodAccess[fullMethod]
+		require.True(t, ok, "missing access level for %s", fullMethod)
+	}
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	testCases := []struct {
+		name          string
+		fullMethod    string
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, payload *token.Payload, err error)
+	}{
+		{
+			name:       "PublicMethod",
+			fullMethod: "/pb.SimpleBank/LoginUser",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name:       "OK",
+			fullMethod: "/pb.SimpleBank/GetAccount",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "user", time.Minute)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload)
+				require.Equal(t, "user", payload.Username)
+			},
+		},
+		{
+			name:       "RefreshToken",
+			fullMethod: "/pb.SimpleBank/GetAccount",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				refreshToken, _, err := tokenMaker.CreateScopedToken("user", string(db.UserRoleDepositor), token.ScopeRefresh, time.Hour)
+				require.NoError(t, err)
+				md := metadata.MD{authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, refreshToken)}}
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name:       "NoAuthorization",
+			fullMethod: "/pb.SimpleBank/GetAccount",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:       "ExpiredToken",
+			fullMethod: "/pb.SimpleBank/GetAccount",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "user", -time.Minute)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:       "PasswordChangedAfterIssue",
+			fullMethod: "/pb.SimpleBank/GetAccount",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ string) (time.Time, error) {
+						return time.Now(), nil
+					})
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Contains(t, err.Error(), token.ErrRevokedToken.Error())
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name:       "UnknownMethod",
+			fullMethod: "/pb.SimpleBank/Unknown",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			server := newTestServer(t, store)
+			interceptor := server.UnaryAuthInterceptor()
+
+			var payload *token.Payload
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				payload, _ = authPayloadFromContext(ctx)
+				return nil, nil
+			}
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.fullMethod}, handler)
+			tc.checkResponse(t, payload, err)
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	interceptor := server.StreamAuthInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/pb.SimpleBank/ListAccounts"}
+
+	var payload *token.Payload
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		payload, _ = authPayloadFromContext(stream.Context())
+		return nil
+	}
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, "user", time.Minute)
+	err := interceptor(nil, &testServerStream{ctx: ctx}, info, handler)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	require.Equal(t, "user", payload.Username)
+
+	payload = nil
+	err = interceptor(nil, &testServerStream{ctx: context.Background()}, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.Nil(t, payload)
+}
@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errInvalidCredentials is returned for unknown usernames and wrong passwords alike, so that
+// a login does not tell which usernames exist
+var errInvalidCredentials = errors.New("incorrect username or password")
+
+// reserveLogin counts the login as failed before its password or code is checked, so that
+// parallel guesses cannot all get past the lockout. It returns ResourceExhausted when the
+// username or the client IP is locked out.
+func (server *Server) reserveLogin(ctx context.Context, username string, clientIP string) (*lockout.Reservation, error) {
+	reservation, err := server.lockout.Reserve(ctx, username, clientIP)
+	if err == nil {
+		return reservation, nil
+	}
+	if errors.Is(err, lockout.ErrLocked) {
+		return nil, status.Errorf(codes.ResourceExhausted, "%s", err)
+	}
+	return nil, status.Errorf(codes.Internal, "failed to record login attempt: %s", err)
+}
+
+// refundLogin takes back a login reserved by reserveLogin once its password or code was right
+func (server *Server) refundLogin(ctx context.Context, reservation *lockout.Reservation) error {
+	if err := server.lockout.Refund(ctx, reservation); err != nil {
+		return status.Errorf(codes.Internal, "failed to refund login attempt: %s", err)
+	}
+	return nil
+}
+
+// checkTransferCode checks the two-factor code of a transfer. Wrong codes count towards the
+// login lockout of the user, or whoever took over a session could guess codes until one is right.
+func (server *Server) checkTransferCode(ctx context.Context, username string, amount money.Amount, code string) error {
+	if code == "" {
+		if err := server.mfa.CheckTransfer(ctx, username, amount, code); err != nil {
+			return mfaError(err)
+		}
+		return nil
+	}
+
+	reservation, err := server.reserveLogin(ctx, username, server.extractMetadata(ctx).ClientIP)
+	if err != nil {
+		return err
+	}
+	if err := server.mfa.CheckTransfer(ctx, username, amount, code); err != nil {
+		return mfaError(err)
+	}
+	return server.refundLogin(ctx, reservation)
+}
@@ -0,0 +1,98 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+		MFATokenDuration:     time.Minute,
+		// failed logins are kept in memory, so login tests need no stubs for them
+		LoginMaxFailures:        3,
+		LoginMaxFailuresPerIP:   10,
+		LoginLockoutDuration:    time.Minute,
+		LoginMaxLockoutDuration: time.Hour,
+	}
+
+	// access tokens of mock users pass the password change check, unless the test
+	// set up GetUserPasswordChangedAt itself before creating the server
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().
+			GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(time.Time{}, nil)
+	}
+
+	loginLockout, err := lockout.New(config, store)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, loginLockout)
+	require.NoError(t, err)
+	return server
+}
+
+func randomUser(t *testing.T) (user db.User, password string) {
+	password = util.RandomPassword()
+	hashedPassword, err := util.HashedPassword(password)
+	require.NoError(t, err)
+
+	user = db.User{
+		Username:        util.RandomUsername(),
+		HashedPassword:  hashedPassword,
+		FullName:        util.RandomFullname(),
+		Email:           util.RandomEmail(),
+		Role:            db.UserRoleDepositor,
+		IsEmailVerified: true,
+	}
+	return
+}
+
+// expectVerifiedUsers lets every user through the email verification check of transfers
+func expectVerifiedUsers(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.User, error) {
+			return db.User{Username: username, Role: db.UserRoleDepositor, IsEmailVerified: true}, nil
+		})
+}
+
+func randomAccount(owner string) db.Account {
+	return db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+	}
+}
+
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, duration time.Duration) context.Context {
+	return newContextWithRoleToken(t, tokenMaker, username, db.UserRoleDepositor, duration)
+}
+
+func newContextWithRoleToken(t *testing.T, tokenMaker token.Maker, username string, role db.UserRole, duration time.Duration) context.Context {
+	accessToken, _, err := tokenMaker.CreateToken(username, string(role), duration)
+	require.NoError(t, err)
+
+	md := metadata.MD{
+		authorizationHeader: []string{
+			fmt.Sprintf("%s %s", authorizationBearer, accessToken),
+		},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
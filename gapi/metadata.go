@@ -0,0 +1,111 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
+	userAgentHeader            = "user-agent"
+	xForwardedForHeader        = "x-forwarded-for"
+)
+
+type Metadata struct {
+	UserAgent string
+	ClientIP  string
+}
+
+// extractMetadata reads the client user agent and IP address from the incoming request
+func (server *Server) extractMetadata(ctx context.Context) *Metadata {
+	mtdt := &Metadata{}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if userAgents := md.Get(grpcGatewayUserAgentHeader); len(userAgents) > 0 {
+		mtdt.UserAgent = userAgents[0]
+	}
+
+	if userAgents := md.Get(userAgentHeader); len(userAgents) > 0 {
+		mtdt.UserAgent = userAgents[0]
+	}
+
+	mtdt.ClientIP = server.clientIP(ctx, md)
+	return mtdt
+}
+
+// clientIP walks the forwarding chain from the connection towards the client and stops at the
+// first hop that is not a trusted proxy, since anyone can send x-forwarded-for. Direct gRPC calls
+// start from the peer address. Calls of the in-process gateway have no peer, they start from the
+// remote address that the gateway appends to x-forwarded-for.
+func (server *Server) clientIP(ctx context.Context, md metadata.MD) string {
+	var hops []string
+	for _, header := range md.Get(xForwardedForHeader) {
+		for _, hop := range strings.Split(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		hops = append(hops, addr)
+	}
+	if len(hops) == 0 {
+		return ""
+	}
+
+	clientIP := hops[len(hops)-1]
+	for i := len(hops) - 2; i >= 0 && server.isTrustedProxy(clientIP); i-- {
+		if net.ParseIP(hops[i]) == nil {
+			break
+		}
+		clientIP = hops[i]
+	}
+	return clientIP
+}
+
+func (server *Server) isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range server.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies accepts IPs and CIDRs like gin.Engine.SetTrustedProxies
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,99 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadataClientIP(t *testing.T) {
+	withPeer := func(ctx context.Context, ip string) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 54321}})
+	}
+	withForwardedFor := func(ctx context.Context, xff string) context.Context {
+		return metadata.NewIncomingContext(ctx, metadata.Pairs(xForwardedForHeader, xff))
+	}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		clientIP string
+	}{
+		{
+			name:     "DirectPeer",
+			ctx:      withPeer(context.Background(), "203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "SpoofedByUntrustedPeer",
+			ctx:      withPeer(withForwardedFor(context.Background(), "198.51.100.1"), "203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "ForwardedByTrustedPeer",
+			ctx:      withPeer(withForwardedFor(context.Background(), "198.51.100.1"), "10.0.0.5"),
+			clientIP: "198.51.100.1",
+		},
+		{
+			name:     "SpoofedThroughTrustedPeer",
+			ctx:      withPeer(withForwardedFor(context.Background(), "192.0.2.1, 198.51.100.1"), "10.0.0.5"),
+			clientIP: "198.51.100.1",
+		},
+		{
+			name:     "ChainOfTrustedProxies",
+			ctx:      withPeer(withForwardedFor(context.Background(), "198.51.100.1, 10.0.0.6"), "10.0.0.5"),
+			clientIP: "198.51.100.1",
+		},
+		{
+			name:     "InvalidForwardedAddress",
+			ctx:      withPeer(withForwardedFor(context.Background(), "not-an-ip"), "10.0.0.5"),
+			clientIP: "10.0.0.5",
+		},
+		{
+			// the in-process gateway appends the remote address of the HTTP request
+			name:     "GatewaySpoofedByClient",
+			ctx:      withForwardedFor(context.Background(), "192.0.2.1, 203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "GatewayBehindTrustedProxy",
+			ctx:      withForwardedFor(context.Background(), "198.51.100.1, 10.0.0.5"),
+			clientIP: "198.51.100.1",
+		},
+		{
+			name:     "Unknown",
+			ctx:      context.Background(),
+			clientIP: "",
+		},
+	}
+
+	server := newTestServer(t, nil)
+	trustedProxies, err := parseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	server.trustedProxies = trustedProxies
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.clientIP, server.extractMetadata(tc.ctx).ClientIP)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"10.0.0.0/8", "127.0.0.1", "::1"})
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+	require.True(t, proxies[1].Contains(net.ParseIP("127.0.0.1")))
+	require.False(t, proxies[1].Contains(net.ParseIP("127.0.0.2")))
+	require.True(t, proxies[2].Contains(net.ParseIP("::1")))
+
+	_, err = parseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+	_, err = parseTrustedProxies([]string{"proxy.local"})
+	require.Error(t, err)
+}
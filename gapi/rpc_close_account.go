@@ -0,0 +1,56 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if req.GetId() < 1 {
+		return nil, invalidArgumentError("id", fmt.Errorf("must be a positive integer"))
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, authPayload, req.GetId(), accountManage)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Status == db.AccountStatusClosed {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] is already closed", account.ID)
+	}
+	// like unfreezing, closing a frozen account is left to staff
+	closeFrozen := requireRole(authPayload, staffRoles...) == nil
+	if account.Status == db.AccountStatusFrozen && !closeFrozen {
+		return nil, status.Errorf(codes.PermissionDenied, "account [%d] is frozen, only staff can close it", account.ID)
+	}
+	if account.Balance != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] balance must be zero to close it", account.ID)
+	}
+
+	account, err = server.store.CloseAccount(ctx, db.CloseAccountParams{
+		ID:          account.ID,
+		CloseFrozen: closeFrozen,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.Aborted, "account changed while closing it, please retry")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to close account: %s", err)
+	}
+
+	res := &pb.CloseAccountResponse{
+		Account: convertAccount(account),
+	}
+	return res, nil
+}
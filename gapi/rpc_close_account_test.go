@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCloseFrozenAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	account.Balance = 0
+	account.Status = db.AccountStatusFrozen
+	closedAccount := account
+	closedAccount.Status = db.AccountStatusClosed
+
+	testCases := []struct {
+		name          string
+		role          db.UserRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CloseAccountResponse, err error)
+	}{
+		{
+			name: "Owner",
+			role: db.UserRoleDepositor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "Staff",
+			role: db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CloseAccount(gomock.Any(), gomock.Eq(db.CloseAccountParams{ID: account.ID, CloseFrozen: true})).
+					Times(1).
+					Return(closedAccount, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, string(db.AccountStatusClosed), res.GetAccount().GetStatus())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				Return(account, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithRoleToken(t, server.tokenMaker, user.Username, tc.role, time.Minute)
+			res, err := server.CloseAccount(ctx, &pb.CloseAccountRequest{Id: account.ID})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if !money.IsSupported(req.GetCurrency()) {
+		return nil, invalidArgumentError("currency", fmt.Errorf("unsupported currency %q", req.GetCurrency()))
+	}
+
+	arg := db.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Balance:  0,
+		Currency: req.GetCurrency(),
+	}
+
+	account, err := server.store.CreateAccount(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation", "foreign_key_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "cannot create account: %s", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	res := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+	return res, nil
+}
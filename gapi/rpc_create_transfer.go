@@ -0,0 +1,134 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/fx"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := validateCreateTransferRequest(req); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, permissionDeniedError(fmt.Errorf("from account doesn't belong to authenticated user"))
+	}
+
+	// read the user rather than the token so that verifying takes effect without logging in again
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+	if !user.IsEmailVerified {
+		return nil, permissionDeniedError(fmt.Errorf("email address is not verified, open the link sent to it first"))
+	}
+	if err := server.checkTransferCode(ctx, user.Username, money.New(req.GetAmount(), fromAccount.Currency), req.GetTotpCode()); err != nil {
+		return nil, err
+	}
+
+	toAccount, err := server.loadAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+	}
+
+	if toAccount.Currency != fromAccount.Currency {
+		conversion, err := server.fxService.Convert(ctx, money.New(req.GetAmount(), fromAccount.Currency), toAccount.Currency)
+		if err != nil {
+			return nil, conversionError(err)
+		}
+		arg.ToAmount = conversion.ConvertedAmount.Units()
+		arg.ExchangeRate = conversion.Rate
+	}
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer: %s", err)
+	}
+
+	res := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEnTry),
+	}
+	return res, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest) error {
+	if req.GetFromAccountId() < 1 {
+		return invalidArgumentError("from_account_id", fmt.Errorf("must be a positive integer"))
+	}
+	if req.GetToAccountId() < 1 {
+		return invalidArgumentError("to_account_id", fmt.Errorf("must be a positive integer"))
+	}
+	if req.GetAmount() <= 0 {
+		return invalidArgumentError("amount", fmt.Errorf("must be greater than 0"))
+	}
+	if !money.IsSupported(req.GetCurrency()) {
+		return invalidArgumentError("currency", fmt.Errorf("unsupported currency %q", req.GetCurrency()))
+	}
+	return nil
+}
+
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.loadAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+		return account, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	return account, nil
+}
+
+func (server *Server) loadAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	return account, nil
+}
+
+func conversionError(err error) error {
+	switch {
+	case errors.Is(err, fx.ErrRateNotFound):
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	case errors.Is(err, fx.ErrAmountTooSmall), errors.Is(err, fx.ErrAmountTooLarge):
+		return invalidArgumentError("amount", err)
+	}
+	return status.Errorf(codes.Internal, "failed to convert amount: %s", err)
+}
@@ -0,0 +1,233 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferAPI(t *testing.T) {
+	amount := int64(10)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account3 := randomAccount(user2.Username)
+
+	account1.Currency = money.USD
+	account2.Currency = money.USD
+	account3.Currency = money.EUR
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      money.USD,
+			},
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "FromAccountNotOwned",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      money.USD,
+			},
+			username: user2.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "CrossCurrency",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      money.USD,
+			},
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExchangeRate{BaseCurrency: money.USD, QuoteCurrency: money.EUR, Rate: "0.9"}, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account3.ID,
+					Amount:        amount,
+					ToAmount:      9,
+					ExchangeRate:  "0.9",
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ExchangeRateNotFound",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      money.USD,
+			},
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().
+					GetExchangeRate(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.ExchangeRate{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "NegativeAmount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        -amount,
+				Currency:      money.USD,
+			},
+			username: user1.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			expectVerifiedUsers(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, tc.username, time.Minute)
+			res, err := server.CreateTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestCreateTransferTOTPLockout(t *testing.T) {
+	user, _ := randomUser(t)
+	account1 := randomAccount(user.Username)
+	account1.Currency = money.USD
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	userTotp := db.UserTotp{
+		Username:    user.Username,
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).AnyTimes().Return(account1, nil)
+	expectVerifiedUsers(store)
+	store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(userTotp, nil)
+	store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	thresholds, err := mfa.ParseTransferThresholds("USD:1000")
+	require.NoError(t, err)
+	server.mfa = mfa.NewService(store, "Simple Bank", thresholds)
+
+	code, err := mfa.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	req := &pb.CreateTransferRequest{
+		FromAccountId: account1.ID,
+		ToAccountId:   account1.ID + 1,
+		Amount:        100000, // 1000 USD
+		Currency:      money.USD,
+		TotpCode:      wrongTOTPCode(code),
+	}
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, time.Minute)
+	for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+		_, err := server.CreateTransfer(ctx, req)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+
+	// once locked out, no more codes are accepted, not even the right one
+	req.TotpCode = code
+	_, err = server.CreateTransfer(ctx, req)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the lockout covers logins too
+	_, err = server.LoginUser(context.Background(), &pb.LoginUserRequest{Username: user.Username, Password: util.RandomPassword()})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func wrongTOTPCode(code string) string {
+	digits := []byte(code)
+	for i := range digits {
+		digits[i] = '0' + (digits[i]-'0'+5)%10
+	}
+	return string(digits)
+}
@@ -0,0 +1,84 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	if err := server.validateCreateUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := util.HashedPassword(req.GetPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+	}
+
+	secretCode, err := util.RandomSecret(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate verification code: %s", err)
+	}
+
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.GetUsername(),
+			HashedPassword: hashedPassword,
+			FullName:       req.GetFullName(),
+			Email:          req.GetEmail(),
+		},
+		SecretCode:           secretCode,
+		VerifyEmailExpiredAt: time.Now().Add(server.config.VerifyEmailDuration),
+	}
+
+	result, err := server.store.CreateUserTx(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "username already exists: %s", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create user: %s", err)
+	}
+
+	// the code is only mailed once it is committed, a retried transaction would mail it again
+	if err := server.sendVerifyEmail(ctx, result.User, result.VerifyEmail); err != nil {
+		return nil, status.Errorf(codes.Internal, "user %s was created but the verification email cannot be sent: %s", result.User.Username, err)
+	}
+
+	res := &pb.CreateUserResponse{
+		User: convertUser(result.User),
+	}
+	return res, nil
+}
+
+// sendVerifyEmail mails the link that verifies the email of a user
+func (server *Server) sendVerifyEmail(ctx context.Context, user db.User, verifyEmail db.VerifyEmail) error {
+	link := mail.VerifyEmailLink(server.config.PublicBaseURL, verifyEmail.ID, verifyEmail.SecretCode)
+	return server.mailer.Send(ctx, mail.NewVerifyEmailMessage(user.Email, user.FullName, link, verifyEmail.ExpiredAt))
+}
+
+func (server *Server) validateCreateUserRequest(req *pb.CreateUserRequest) error {
+	if err := server.validate.Var(req.GetUsername(), "required,alphanum"); err != nil {
+		return invalidArgumentError("username", err)
+	}
+	if err := server.validate.Var(req.GetPassword(), "required,min=6"); err != nil {
+		return invalidArgumentError("password", err)
+	}
+	if err := server.validate.Var(req.GetFullName(), "required"); err != nil {
+		return invalidArgumentError("full_name", err)
+	}
+	if err := server.validate.Var(req.GetEmail(), "required,email"); err != nil {
+		return invalidArgumentError("email", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lamdangtung/golang-sample-bank/pb"
+)
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if req.GetId() < 1 {
+		return nil, invalidArgumentError("id", fmt.Errorf("must be a positive integer"))
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, authPayload, req.GetId(), accountView)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// accountCursor is the position of the last account of a page, accounts are listed by ID
+type accountCursor struct {
+	ID int64 `json:"id"`
+}
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	limit, err := server.pageLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	owner := authPayload.Username
+	if len(req.GetOwner()) > 0 && req.GetOwner() != owner {
+		if err := requireRole(authPayload, staffRoles...); err != nil {
+			return nil, err
+		}
+		owner = req.GetOwner()
+	}
+
+	scope := "accounts:" + owner
+	var after accountCursor
+	if len(req.GetAfter()) > 0 {
+		if err := server.cursors.Decode(scope, req.GetAfter(), &after); err != nil {
+			return nil, invalidArgumentError("after", err)
+		}
+	}
+
+	arg := db.ListAccountsAfterParams{
+		Owner:   owner,
+		AfterID: after.ID,
+		Limit:   limit + 1,
+	}
+	accounts, err := server.store.ListAccountsAfter(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	accounts, more := cursor.SplitPage(accounts, limit)
+	res := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		res.Accounts = append(res.Accounts, convertAccount(account))
+	}
+
+	if more {
+		last := accounts[len(accounts)-1]
+		res.NextCursor, err = server.cursors.Encode(scope, accountCursor{ID: last.ID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode cursor: %s", err)
+		}
+	}
+	return res, nil
+}
+
+// pageLimit returns the requested page size, or the default one
+func (server *Server) pageLimit(limit int32) (int32, error) {
+	if err := server.validate.Var(limit, "omitempty,min=1,max=100"); err != nil {
+		return 0, invalidArgumentError("limit", err)
+	}
+	if limit == 0 {
+		return cursor.DefaultLimit, nil
+	}
+	return limit, nil
+}
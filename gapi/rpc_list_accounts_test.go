@@ -0,0 +1,60 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListAccountsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	accounts := make([]db.Account, 3)
+	for i := range accounts {
+		accounts[i] = randomAccount(user.Username)
+		accounts[i].ID = int64(10 + i)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{Owner: user.Username, Limit: 3})).
+		Times(1).
+		Return(accounts, nil)
+	store.EXPECT().
+		ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{Owner: user.Username, AfterID: 11, Limit: 3})).
+		Times(1).
+		Return(accounts[2:], nil)
+
+	server := newTestServer(t, store)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, time.Minute)
+
+	res, err := server.ListAccounts(ctx, &pb.ListAccountsRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, res.GetAccounts(), 2)
+	require.Equal(t, int64(11), res.GetAccounts()[1].GetId())
+	require.NotEmpty(t, res.GetNextCursor())
+	next := res.GetNextCursor()
+
+	res, err = server.ListAccounts(ctx, &pb.ListAccountsRequest{Limit: 2, After: next})
+	require.NoError(t, err)
+	require.Len(t, res.GetAccounts(), 1)
+	require.Equal(t, int64(12), res.GetAccounts()[0].GetId())
+	require.Empty(t, res.GetNextCursor())
+
+	// the cursor only pages through the accounts of the owner it was issued for
+	staffCtx := newContextWithRoleToken(t, server.tokenMaker, "banker", db.UserRoleBanker, time.Minute)
+	_, err = server.ListAccounts(staffCtx, &pb.ListAccountsRequest{Owner: "other", After: next})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.ListAccounts(ctx, &pb.ListAccountsRequest{Limit: 101})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// entryCursor is the position of the last entry of a page, entries are listed by ID
+type entryCursor struct {
+	ID int64 `json:"id"`
+}
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if req.GetAccountId() < 1 {
+		return nil, invalidArgumentError("account_id", fmt.Errorf("must be a positive integer"))
+	}
+	limit, err := server.pageLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.getAuthorizedAccount(ctx, authPayload, req.GetAccountId(), accountView)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := fmt.Sprintf("entries:%d", req.GetAccountId())
+	var after entryCursor
+	if len(req.GetAfter()) > 0 {
+		if err := server.cursors.Decode(scope, req.GetAfter(), &after); err != nil {
+			return nil, invalidArgumentError("after", err)
+		}
+	}
+
+	arg := db.SearchEntriesParams{
+		AccountID: req.GetAccountId(),
+		AfterID:   sql.NullInt64{Int64: after.ID, Valid: after.ID != 0},
+		OrderBy:   "created_at",
+		Limit:     limit + 1,
+	}
+	entries, err := server.store.SearchEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	entries, more := cursor.SplitPage(entries, limit)
+	res := &pb.ListEntriesResponse{
+		Entries: make([]*pb.Entry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, convertEntry(entry))
+	}
+
+	if more {
+		last := entries[len(entries)-1]
+		res.NextCursor, err = server.cursors.Encode(scope, entryCursor{ID: last.ID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode cursor: %s", err)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	entries := make([]db.Entry, 3)
+	for i := range entries {
+		entries[i] = db.Entry{ID: int64(20 + i), AccountID: account.ID, Amount: 100}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		AnyTimes().
+		Return(account, nil)
+	store.EXPECT().
+		SearchEntries(gomock.Any(), gomock.Eq(db.SearchEntriesParams{
+			AccountID: account.ID,
+			OrderBy:   "created_at",
+			Limit:     3,
+		})).
+		Times(1).
+		Return(entries, nil)
+	store.EXPECT().
+		SearchEntries(gomock.Any(), gomock.Eq(db.SearchEntriesParams{
+			AccountID: account.ID,
+			AfterID:   sql.NullInt64{Int64: 21, Valid: true},
+			OrderBy:   "created_at",
+			Limit:     3,
+		})).
+		Times(1).
+		Return(entries[2:], nil)
+
+	server := newTestServer(t, store)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, time.Minute)
+
+	res, err := server.ListEntries(ctx, &pb.ListEntriesRequest{AccountId: account.ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, res.GetEntries(), 2)
+	require.NotEmpty(t, res.GetNextCursor())
+
+	res, err = server.ListEntries(ctx, &pb.ListEntriesRequest{AccountId: account.ID, Limit: 2, After: res.GetNextCursor()})
+	require.NoError(t, err)
+	require.Len(t, res.GetEntries(), 1)
+	require.Equal(t, int64(22), res.GetEntries()[0].GetId())
+	require.Empty(t, res.GetNextCursor())
+
+	_, err = server.ListEntries(ctx, &pb.ListEntriesRequest{AccountId: account.ID, After: fmt.Sprintf("%d", entries[1].ID)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,113 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
+	if err := server.validateLoginUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	reservation, err := server.reserveLogin(ctx, req.GetUsername(), server.extractMetadata(ctx).ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.store.GetUser(ctx, req.GetUsername())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			util.CheckPasswordOfUnknownUser(req.GetPassword())
+			return nil, unauthenticatedError(errInvalidCredentials)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to find user: %s", err)
+	}
+
+	err = util.CheckPassword(req.GetPassword(), user.HashedPassword)
+	if err != nil {
+		return nil, unauthenticatedError(errInvalidCredentials)
+	}
+	if err := server.refundLogin(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := server.mfa.Enabled(ctx, user.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check two-factor authentication: %s", err)
+	}
+	if mfaEnabled {
+		mfaToken, mfaPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeMFAPending, server.config.MFATokenDuration)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create MFA token: %s", err)
+		}
+		res := &pb.LoginUserResponse{
+			MfaRequired:       true,
+			MfaToken:          mfaToken,
+			MfaTokenExpiredAt: timestamppb.New(mfaPayload.ExpiredAt),
+		}
+		return res, nil
+	}
+
+	return server.startSession(ctx, user)
+}
+
+// startSession completes a login: it clears the failed logins of the user, issues the
+// access and refresh tokens and records the session of the refresh token
+func (server *Server) startSession(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	if err := server.lockout.Reset(ctx, user.Username); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset login attempts: %s", err)
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), server.config.AccessTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access token: %s", err)
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeRefresh, server.config.RefreshTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %s", err)
+	}
+
+	mtdt := server.extractMetadata(ctx)
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     refreshPayload.Username,
+		UserAgent:    mtdt.UserAgent,
+		ClientIp:     mtdt.ClientIP,
+		IsBlocked:    false,
+		ExpiredAt:    refreshPayload.ExpiredAt,
+		RefreshToken: refreshToken,
+		FamilyID:     refreshPayload.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create session: %s", err)
+	}
+
+	res := &pb.LoginUserResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiredAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiredAt: timestamppb.New(session.ExpiredAt),
+		User:                  convertUser(user),
+	}
+	return res, nil
+}
+
+func (server *Server) validateLoginUserRequest(req *pb.LoginUserRequest) error {
+	if err := server.validate.Var(req.GetUsername(), "required,alphanum"); err != nil {
+		return invalidArgumentError("username", err)
+	}
+	if err := server.validate.Var(req.GetPassword(), "required,min=6"); err != nil {
+		return invalidArgumentError("password", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoginUserMFA is the second step of the login of users with two-factor authentication, it
+// exchanges the MFA token given by LoginUser and a code for access and refresh tokens
+func (server *Server) LoginUserMFA(ctx context.Context, req *pb.LoginUserMFARequest) (*pb.LoginUserResponse, error) {
+	if err := server.validate.Var(req.GetCode(), "required"); err != nil {
+		return nil, invalidArgumentError("code", err)
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.GetMfaToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if err := payload.CheckScope(token.ScopeMFAPending); err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	// codes are guessed more easily than passwords, so they count towards the same lockout
+	reservation, err := server.reserveLogin(ctx, payload.Username, server.extractMetadata(ctx).ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find user: %s", err)
+	}
+	// a login started with the previous password cannot be completed
+	if err := payload.CheckPasswordChangedAt(user.PasswordChangedAt); err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := server.mfa.VerifyOrRecover(ctx, user.Username, req.GetCode()); err != nil {
+		if rpcErr := mfaError(err); status.Code(rpcErr) == codes.Internal {
+			return nil, rpcErr
+		}
+		return nil, unauthenticatedError(err)
+	}
+	if err := server.refundLogin(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	return server.startSession(ctx, user)
+}
+
+// mfaError maps errors of the mfa service to gRPC status errors
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, mfa.ErrAlreadyEnabled), errors.Is(err, mfa.ErrNotEnabled), errors.Is(err, mfa.ErrNotEnrolled):
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	case errors.Is(err, mfa.ErrInvalidCode), errors.Is(err, mfa.ErrCodeRequired):
+		return permissionDeniedError(err)
+	}
+	return status.Errorf(codes.Internal, "failed to check two-factor code: %s", err)
+}
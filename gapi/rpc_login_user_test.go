@@ -0,0 +1,281 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginUserAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		req           *pb.LoginUserRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.UserTotp{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{
+							ID:           arg.ID,
+							Username:     arg.Username,
+							RefreshToken: arg.RefreshToken,
+							ExpiredAt:    arg.ExpiredAt,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+			},
+		},
+		{
+			name: "MFARequired",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.UserTotp{Username: user.Username, ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetMfaRequired())
+				require.NotEmpty(t, res.GetMfaToken())
+				require.Empty(t, res.GetAccessToken())
+				require.Empty(t, res.GetRefreshToken())
+			},
+		},
+		{
+			name: "UserNotFound",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				// unknown usernames get the same error as wrong passwords
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Contains(t, err.Error(), errInvalidCredentials.Error())
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: "incorrect",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidUsername",
+			req: &pb.LoginUserRequest{
+				Username: "invalid-user#1",
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			res, err := server.LoginUser(context.Background(), tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestLoginUserLockout(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(3).Return(user, nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	req := &pb.LoginUserRequest{Username: user.Username, Password: "incorrect"}
+	for i := 0; i < int(server.config.LoginMaxFailures); i++ {
+		_, err := server.LoginUser(context.Background(), req)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	// once locked out, even the right password is refused without being checked
+	req.Password = password
+	_, err := server.LoginUser(context.Background(), req)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestLoginUserMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+	userTotp := db.UserTotp{
+		Username:    user.Username,
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	validCode := func(t *testing.T) string {
+		code, err := mfa.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		return code
+	}
+	mfaToken := func(t *testing.T, tokenMaker token.Maker) string {
+		mfaToken, _, err := tokenMaker.CreateScopedToken(user.Username, string(user.Role), token.ScopeMFAPending, time.Minute)
+		require.NoError(t, err)
+		return mfaToken
+	}
+
+	testCases := []struct {
+		name          string
+		req           func(t *testing.T, tokenMaker token.Maker) *pb.LoginUserMFARequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: func(t *testing.T, tokenMaker token.Maker) *pb.LoginUserMFARequest {
+				return &pb.LoginUserMFARequest{MfaToken: mfaToken(t, tokenMaker), Code: validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userTotp, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(userTotp, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.False(t, res.GetMfaRequired())
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+			},
+		},
+		{
+			name: "ReplayedCode",
+			req: func(t *testing.T, tokenMaker token.Maker) *pb.LoginUserMFARequest {
+				return &pb.LoginUserMFARequest{MfaToken: mfaToken(t, tokenMaker), Code: validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userTotp, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(db.UserTotp{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "AccessTokenInsteadOfMFAToken",
+			req: func(t *testing.T, tokenMaker token.Maker) *pb.LoginUserMFARequest {
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, string(user.Role), time.Minute)
+				require.NoError(t, err)
+				return &pb.LoginUserMFARequest{MfaToken: accessToken, Code: validCode(t)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			res, err := server.LoginUserMFA(context.Background(), tc.req(t, server.tokenMaker))
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
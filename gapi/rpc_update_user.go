@@ -0,0 +1,105 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUser changes the fields of the authenticated user listed in the update mask.
+// A new email is unverified until the user opens the link sent to it.
+func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	paths, err := updateUserPaths(req)
+	if err != nil {
+		return nil, invalidArgumentError("update_mask", err)
+	}
+
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: authPayload.Username,
+		},
+		VerifyEmailExpiredAt: time.Now().Add(server.config.VerifyEmailDuration),
+	}
+	for _, path := range paths {
+		switch path {
+		case "full_name":
+			if err := server.validate.Var(req.GetFullName(), "required"); err != nil {
+				return nil, invalidArgumentError("full_name", err)
+			}
+			arg.FullName = sql.NullString{String: req.GetFullName(), Valid: true}
+		case "email":
+			if err := server.validate.Var(req.GetEmail(), "required,email"); err != nil {
+				return nil, invalidArgumentError("email", err)
+			}
+			arg.Email = sql.NullString{String: req.GetEmail(), Valid: true}
+
+			arg.SecretCode, err = util.RandomSecret(32)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to generate verification code: %s", err)
+			}
+		}
+	}
+
+	result, err := server.store.UpdateUserTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "email already exists: %s", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user: %s", err)
+	}
+
+	if result.EmailChanged {
+		if err := server.sendVerifyEmail(ctx, result.User, result.VerifyEmail); err != nil {
+			return nil, status.Errorf(codes.Internal, "email was changed but the verification email cannot be sent: %s", err)
+		}
+	}
+
+	res := &pb.UpdateUserResponse{
+		User: convertUser(result.User),
+	}
+	return res, nil
+}
+
+// updateUserPaths returns the fields to update. Without an update mask these are the fields
+// that have a value, as the HTTP gateway does not fill the mask in.
+func updateUserPaths(req *pb.UpdateUserRequest) ([]string, error) {
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		if req.GetFullName() != "" {
+			paths = append(paths, "full_name")
+		}
+		if req.GetEmail() != "" {
+			paths = append(paths, "email")
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("at least one of full_name and email is required")
+		}
+		return paths, nil
+	}
+
+	for _, path := range paths {
+		if path != "full_name" && path != "email" {
+			return nil, fmt.Errorf("unsupported path %q, supported paths are full_name and email", path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,202 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestUpdateUserAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	newFullName := util.RandomFullname()
+	newEmail := util.RandomEmail()
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateUserRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message)
+	}{
+		{
+			name: "FullNameInMask",
+			req: &pb.UpdateUserRequest{
+				FullName:   newFullName,
+				Email:      newEmail,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"full_name"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, sql.NullString{String: newFullName, Valid: true}, arg.FullName)
+						require.False(t, arg.Email.Valid)
+
+						updated := user
+						updated.FullName = newFullName
+						return db.UpdateUserTxResult{User: updated}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.NoError(t, err)
+				require.Equal(t, newFullName, res.GetUser().GetFullName())
+				require.Equal(t, user.Email, res.GetUser().GetEmail())
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name: "EmailWithoutMask",
+			req:  &pb.UpdateUserRequest{Email: newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						require.False(t, arg.FullName.Valid)
+						require.Equal(t, sql.NullString{String: newEmail, Valid: true}, arg.Email)
+						require.NotEmpty(t, arg.SecretCode)
+
+						updated := user
+						updated.Email = newEmail
+						updated.IsEmailVerified = false
+						verifyEmail := db.VerifyEmail{ID: 1, Username: user.Username, Email: newEmail, SecretCode: arg.SecretCode}
+						return db.UpdateUserTxResult{User: updated, EmailChanged: true, VerifyEmail: verifyEmail}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.NoError(t, err)
+				require.Equal(t, newEmail, res.GetUser().GetEmail())
+				require.False(t, res.GetUser().GetIsEmailVerified())
+
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{newEmail}, messages[0].To)
+			},
+		},
+		{
+			name: "EmptyFullNameInMask",
+			req: &pb.UpdateUserRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"full_name"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "UnsupportedPath",
+			req: &pb.UpdateUserRequest{
+				FullName:   newFullName,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"username"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NothingToUpdate",
+			req:  &pb.UpdateUserRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidEmail",
+			req:  &pb.UpdateUserRequest{Email: "invalid-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "DuplicateEmail",
+			req:  &pb.UpdateUserRequest{Email: newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, &pq.Error{Code: "23505"})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.AlreadyExists, status.Code(err))
+				// nothing is mailed for an email change that was not committed
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.UpdateUserRequest{FullName: newFullName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error, messages []mail.Message) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateUser(ctx, tc.req)
+			tc.checkResponse(t, res, err, server.mailer.(*mail.MemoryMailer).Messages())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lamdangtung/golang-sample-bank/cursor"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/fx"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/mail"
+	"github.com/lamdangtung/golang-sample-bank/mfa"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/token"
+	"github.com/lamdangtung/golang-sample-bank/util"
+)
+
+// Server serves gRPC requests for our banking service
+type Server struct {
+	pb.UnimplementedSimpleBankServer
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	fxService  *fx.Service
+	validate   *validator.Validate
+	mailer     mail.Mailer
+	mfa        *mfa.Service
+	lockout    *lockout.Tracker
+	cursors    cursor.Codec
+	// trustedProxies may forward the client IP in x-forwarded-for, see clientIP
+	trustedProxies []*net.IPNet
+}
+
+// NewServer creates a new gRPC server. loginLockout is shared with the other servers, so that
+// failed logins count the same whichever server they hit.
+func NewServer(config util.Config, store db.Store, loginLockout *lockout.Tracker) (*Server, error) {
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+	mailer, err := mail.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
+	}
+	thresholds, err := mfa.ParseTransferThresholds(config.MFATransferThresholds)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse MFA transfer thresholds: %w", err)
+	}
+	trustedProxies, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse trusted proxies: %w", err)
+	}
+
+	server := &Server{
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		fxService:      fx.NewService(store),
+		validate:       validator.New(),
+		mailer:         mailer,
+		mfa:            mfa.NewService(store, config.TOTPIssuer, thresholds),
+		lockout:        loginLockout,
+		cursors:        cursor.NewCodec(config.TokenSymmetricKey),
+		trustedProxies: trustedProxies,
+	}
+	return server, nil
+}
@@ -0,0 +1,227 @@
+// Package lockout counts failed logins per username and per client IP and locks them out
+// for a time that doubles with every further failure
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/util"
+)
+
+// ErrLocked is matched by the LockedError returned by Reserve
+var ErrLocked = errors.New("too many failed login attempts")
+
+// LockedError tells how long a username or client IP stays locked out
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, try again in %s", ErrLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Record is the failed logins of a key since its last reset
+type Record struct {
+	Failures     int32
+	LastFailedAt time.Time
+}
+
+// Store keeps the records of keys. Implementations must be safe for concurrent use and
+// count concurrent failures of a key exactly.
+type Store interface {
+	// Fail counts a failed login of key at failedAt, failures before forgetBefore are forgotten first.
+	// It also returns the LastFailedAt before this failure, or failedAt for new keys.
+	Fail(ctx context.Context, key string, failedAt time.Time, forgetBefore time.Time) (Record, time.Time, error)
+	// Refund takes back the failure counted at failedAt. LastFailedAt goes back to previousFailedAt
+	// unless another failure was counted since, so that logins that turned out right do not keep
+	// the failures of a key from being forgotten.
+	Refund(ctx context.Context, key string, failedAt time.Time, previousFailedAt time.Time) error
+	// Get returns the zero Record for keys without failures
+	Get(ctx context.Context, key string) (Record, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy sets when and for how long logins are locked out
+type Policy struct {
+	// MaxFailures of a username are allowed before it is locked out
+	MaxFailures int32
+	// MaxFailuresPerIP is usually higher than MaxFailures since users may share an IP
+	MaxFailuresPerIP int32
+	// LockoutDuration is the first lockout, it doubles with every failure after it
+	LockoutDuration time.Duration
+	// MaxLockoutDuration caps the lockout. Failures are forgotten once none happened for this long.
+	MaxLockoutDuration time.Duration
+}
+
+func (policy Policy) validate() error {
+	if policy.MaxFailures < 1 || policy.MaxFailuresPerIP < 1 {
+		return errors.New("maximum failures must be positive")
+	}
+	if policy.LockoutDuration <= 0 || policy.MaxLockoutDuration < policy.LockoutDuration {
+		return errors.New("lockout duration must be positive and at most the maximum lockout duration")
+	}
+	return nil
+}
+
+// lockoutDuration is how long the key stays locked after its last failure
+func (policy Policy) lockoutDuration(failures int32, maxFailures int32) time.Duration {
+	if failures < maxFailures {
+		return 0
+	}
+
+	duration := policy.LockoutDuration
+	for i := maxFailures; i < failures && duration < policy.MaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > policy.MaxLockoutDuration {
+		duration = policy.MaxLockoutDuration
+	}
+	return duration
+}
+
+// Tracker counts failed logins. Failures of unknown usernames are counted like any other,
+// so that a lockout does not tell whether a username exists.
+type Tracker struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+func NewTracker(store Store, policy Policy) (*Tracker, error) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	return &Tracker{
+		store:  store,
+		policy: policy,
+		now:    time.Now,
+	}, nil
+}
+
+// New creates the tracker configured by the LOGIN_* settings. config.LoginAttemptStore selects
+// where failures are kept: postgres shares them between every process of the database and memory
+// keeps them in the tracker, so only the servers given the same tracker count them together and
+// they are forgotten on restart. An empty LoginAttemptStore selects memory.
+func New(config util.Config, store db.Store) (*Tracker, error) {
+	policy := Policy{
+		MaxFailures:        int32(config.LoginMaxFailures),
+		MaxFailuresPerIP:   int32(config.LoginMaxFailuresPerIP),
+		LockoutDuration:    config.LoginLockoutDuration,
+		MaxLockoutDuration: config.LoginMaxLockoutDuration,
+	}
+
+	switch config.LoginAttemptStore {
+	case "postgres":
+		return NewTracker(NewPostgresStore(store), policy)
+	case "memory", "":
+		return NewTracker(NewMemoryStore(), policy)
+	default:
+		return nil, fmt.Errorf("unknown login attempt store %q", config.LoginAttemptStore)
+	}
+}
+
+type limit struct {
+	key         string
+	maxFailures int32
+}
+
+// limits lists the keys of a login, the client IP is skipped when it is unknown
+func (tracker *Tracker) limits(username string, clientIP string) []limit {
+	limits := []limit{{key: usernameKey(username), maxFailures: tracker.policy.MaxFailures}}
+	if clientIP != "" {
+		limits = append(limits, limit{key: "ip:" + clientIP, maxFailures: tracker.policy.MaxFailuresPerIP})
+	}
+	return limits
+}
+
+func usernameKey(username string) string {
+	return "user:" + username
+}
+
+// Reservation is a login counted as failed by Reserve, see Refund
+type Reservation struct {
+	failures []reservedFailure
+}
+
+type reservedFailure struct {
+	key              string
+	failedAt         time.Time
+	previousFailedAt time.Time
+}
+
+// Reserve counts a login of the username from the client IP as failed before its password or
+// code is checked, and returns a LockedError without counting it when either is locked out.
+// Logins that turn out right are taken back with Refund. Counting first keeps parallel guesses
+// within the limit: a login is refused when the guesses that ran alongside it used up the
+// failures left when it started.
+func (tracker *Tracker) Reserve(ctx context.Context, username string, clientIP string) (*Reservation, error) {
+	now := tracker.now()
+	forgetBefore := now.Add(-tracker.policy.MaxLockoutDuration)
+	limits := tracker.limits(username, clientIP)
+
+	// allowed is the failure count each key may reach with this login: the next failure once
+	// a lockout has ended, otherwise up to the maximum failures
+	allowed := make([]int32, len(limits))
+	var retryAfter time.Duration
+	for i, limit := range limits {
+		record, err := tracker.store.Get(ctx, limit.key)
+		if err != nil {
+			return nil, err
+		}
+
+		lockedUntil := record.LastFailedAt.Add(tracker.policy.lockoutDuration(record.Failures, limit.maxFailures))
+		if wait := lockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+		allowed[i] = max(record.Failures+1, limit.maxFailures)
+	}
+	if retryAfter > 0 {
+		return nil, &LockedError{RetryAfter: retryAfter}
+	}
+
+	reservation := &Reservation{}
+	for i, limit := range limits {
+		record, previousFailedAt, err := tracker.store.Fail(ctx, limit.key, now, forgetBefore)
+		if err != nil {
+			return nil, err
+		}
+		reservation.failures = append(reservation.failures, reservedFailure{
+			key:              limit.key,
+			failedAt:         now,
+			previousFailedAt: previousFailedAt,
+		})
+
+		if record.Failures > allowed[i] {
+			if err := tracker.Refund(ctx, reservation); err != nil {
+				return nil, err
+			}
+			return nil, &LockedError{RetryAfter: tracker.policy.lockoutDuration(allowed[i], limit.maxFailures)}
+		}
+	}
+	return reservation, nil
+}
+
+// Refund takes back a login counted by Reserve once its password or code turned out right.
+// Logins that end in an internal error stay counted.
+func (tracker *Tracker) Refund(ctx context.Context, reservation *Reservation) error {
+	for _, failure := range reservation.failures {
+		if err := tracker.store.Refund(ctx, failure.key, failure.failedAt, failure.previousFailedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset forgets the failures of a username after a successful login, or when an admin unlocks it.
+// Failures of client IPs are kept, or a user could clear them between guesses at other accounts.
+func (tracker *Tracker) Reset(ctx context.Context, username string) error {
+	return tracker.store.Reset(ctx, usernameKey(username))
+}
@@ -0,0 +1,267 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = Policy{
+	MaxFailures:        3,
+	MaxFailuresPerIP:   5,
+	LockoutDuration:    time.Minute,
+	MaxLockoutDuration: 10 * time.Minute,
+}
+
+func newTestTracker(t *testing.T) (*Tracker, *time.Time) {
+	tracker, err := NewTracker(NewMemoryStore(), testPolicy)
+	require.NoError(t, err)
+
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func reserve(t *testing.T, tracker *Tracker, username string, clientIP string) *Reservation {
+	reservation, err := tracker.Reserve(context.Background(), username, clientIP)
+	require.NoError(t, err)
+	return reservation
+}
+
+func requireLocked(t *testing.T, tracker *Tracker, username string, clientIP string) {
+	_, err := tracker.Reserve(context.Background(), username, clientIP)
+	require.ErrorIs(t, err, ErrLocked)
+}
+
+func TestNew(t *testing.T) {
+	config := util.Config{
+		LoginMaxFailures:        5,
+		LoginMaxFailuresPerIP:   50,
+		LoginLockoutDuration:    time.Minute,
+		LoginMaxLockoutDuration: time.Hour,
+	}
+
+	tracker, err := New(config, nil)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryStore{}, tracker.store)
+
+	config.LoginAttemptStore = "postgres"
+	tracker, err = New(config, nil)
+	require.NoError(t, err)
+	require.IsType(t, &PostgresStore{}, tracker.store)
+
+	config.LoginAttemptStore = "redis"
+	_, err = New(config, nil)
+	require.Error(t, err)
+
+	_, err = New(util.Config{}, nil)
+	require.Error(t, err)
+}
+
+func TestLockoutDuration(t *testing.T) {
+	testCases := []struct {
+		failures int32
+		duration time.Duration
+	}{
+		{failures: 0, duration: 0},
+		{failures: 2, duration: 0},
+		{failures: 3, duration: time.Minute},
+		{failures: 4, duration: 2 * time.Minute},
+		{failures: 6, duration: 8 * time.Minute},
+		{failures: 7, duration: 10 * time.Minute},
+		{failures: 1000, duration: 10 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.duration, testPolicy.lockoutDuration(tc.failures, testPolicy.MaxFailures), "failures: %d", tc.failures)
+	}
+}
+
+func TestTrackerUsername(t *testing.T) {
+	tracker, now := newTestTracker(t)
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	for i := 0; i < 3; i++ {
+		reserve(t, tracker, username, "")
+	}
+
+	_, err := tracker.Reserve(ctx, username, "")
+	require.ErrorIs(t, err, ErrLocked)
+	var lockedErr *LockedError
+	require.ErrorAs(t, err, &lockedErr)
+	require.Equal(t, time.Minute, lockedErr.RetryAfter)
+
+	// other usernames are not affected
+	reserve(t, tracker, util.RandomOwner(), "")
+
+	// the next failure after the lockout doubles it
+	*now = now.Add(time.Minute)
+	reserve(t, tracker, username, "")
+	_, err = tracker.Reserve(ctx, username, "")
+	require.ErrorAs(t, err, &lockedErr)
+	require.Equal(t, 2*time.Minute, lockedErr.RetryAfter)
+
+	require.NoError(t, tracker.Reset(ctx, username))
+	reserve(t, tracker, username, "")
+}
+
+func TestTrackerClientIP(t *testing.T) {
+	tracker, _ := newTestTracker(t)
+	ctx := context.Background()
+	clientIP := "203.0.113.7"
+
+	// spraying many usernames from one IP locks the IP
+	for i := 0; i < 5; i++ {
+		reserve(t, tracker, util.RandomOwner(), clientIP)
+	}
+	requireLocked(t, tracker, util.RandomOwner(), clientIP)
+	reserve(t, tracker, util.RandomOwner(), "198.51.100.1")
+
+	// a successful login does not clear the failures of its IP
+	username := util.RandomOwner()
+	require.NoError(t, tracker.Reset(ctx, username))
+	requireLocked(t, tracker, username, clientIP)
+}
+
+func TestTrackerRefund(t *testing.T) {
+	tracker, _ := newTestTracker(t)
+	ctx := context.Background()
+	username := util.RandomOwner()
+	clientIP := "203.0.113.7"
+
+	// logins that turn out right are never counted, whether for the username or the IP
+	for i := 0; i < 10; i++ {
+		reservation := reserve(t, tracker, username, clientIP)
+		require.NoError(t, tracker.Refund(ctx, reservation))
+	}
+
+	record, err := tracker.store.Get(ctx, "ip:"+clientIP)
+	require.NoError(t, err)
+	require.Zero(t, record.Failures)
+}
+
+func TestTrackerForgetsOldFailures(t *testing.T) {
+	tracker, now := newTestTracker(t)
+	username := util.RandomOwner()
+
+	reserve(t, tracker, username, "")
+	reserve(t, tracker, username, "")
+
+	*now = now.Add(testPolicy.MaxLockoutDuration + time.Second)
+	reserve(t, tracker, username, "")
+	reserve(t, tracker, username, "")
+}
+
+func TestTrackerForgetsFailuresBetweenRefunds(t *testing.T) {
+	tracker, now := newTestTracker(t)
+	ctx := context.Background()
+	username := util.RandomOwner()
+	clientIP := "203.0.113.7"
+
+	// an IP shared behind a NAT: a few wrong passwords, then someone logs in every minute
+	for i := 0; i < 4; i++ {
+		reserve(t, tracker, util.RandomOwner(), clientIP)
+	}
+	for i := 0; i < 15; i++ {
+		*now = now.Add(time.Minute)
+		reservation := reserve(t, tracker, username, clientIP)
+		require.NoError(t, tracker.Refund(ctx, reservation))
+	}
+
+	// the wrong passwords are forgotten as if the successful logins never happened
+	record, err := tracker.store.Get(ctx, "ip:"+clientIP)
+	require.NoError(t, err)
+	require.Zero(t, record.Failures)
+	for i := 0; i < int(testPolicy.MaxFailuresPerIP); i++ {
+		reserve(t, tracker, util.RandomOwner(), clientIP)
+	}
+	requireLocked(t, tracker, util.RandomOwner(), clientIP)
+
+	// a refund keeps the time of a failure counted after it
+	*now = now.Add(testPolicy.MaxLockoutDuration + time.Second)
+	reservation := reserve(t, tracker, username, clientIP)
+	*now = now.Add(time.Second)
+	reserve(t, tracker, util.RandomOwner(), clientIP)
+	require.NoError(t, tracker.Refund(ctx, reservation))
+
+	record, err = tracker.store.Get(ctx, "ip:"+clientIP)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), record.Failures)
+	require.Equal(t, *now, record.LastFailedAt)
+}
+
+func TestTrackerConcurrentReserve(t *testing.T) {
+	tracker, now := newTestTracker(t)
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	// parallel guesses all pass a check made before any of them failed, only the reservations
+	// within the limit may go on to check their password
+	reserve := func(n int) int {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		reserved := 0
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := tracker.Reserve(ctx, username, "")
+				if err != nil {
+					require.ErrorIs(t, err, ErrLocked)
+					return
+				}
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return reserved
+	}
+
+	require.Equal(t, int(testPolicy.MaxFailures), reserve(50))
+
+	// once the lockout ended, a burst gets a single guess before the doubled lockout
+	*now = now.Add(time.Minute)
+	require.Equal(t, 1, reserve(50))
+}
+
+func TestMemoryStoreConcurrentFailures(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := store.Fail(context.Background(), "user:alice", now, now.Add(-time.Hour))
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	record, err := store.Get(context.Background(), "user:alice")
+	require.NoError(t, err)
+	require.Equal(t, int32(50), record.Failures)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore()
+	old := time.Now().Add(-2 * time.Hour)
+	now := time.Now()
+
+	for i := 0; i < minSweepSize-1; i++ {
+		_, _, err := store.Fail(context.Background(), util.RandomString(16), old, old.Add(-time.Hour))
+		require.NoError(t, err)
+	}
+	_, _, err := store.Fail(context.Background(), "user:alice", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, store.records, 1)
+}
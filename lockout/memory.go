@@ -0,0 +1,79 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minSweepSize is the number of records from which MemoryStore starts dropping forgotten ones
+const minSweepSize = 1024
+
+// MemoryStore keeps the records in the process
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	sweepSize int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:   make(map[string]Record),
+		sweepSize: minSweepSize,
+	}
+}
+
+func (store *MemoryStore) Fail(ctx context.Context, key string, failedAt time.Time, forgetBefore time.Time) (Record, time.Time, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	record, ok := store.records[key]
+	previousFailedAt := failedAt
+	if ok {
+		previousFailedAt = record.LastFailedAt
+	}
+	if record.LastFailedAt.Before(forgetBefore) {
+		record.Failures = 0
+	}
+	record.Failures++
+	record.LastFailedAt = failedAt
+	store.records[key] = record
+
+	// logins with ever new usernames must not grow the map without bound
+	if len(store.records) >= store.sweepSize {
+		for key, record := range store.records {
+			if record.LastFailedAt.Before(forgetBefore) {
+				delete(store.records, key)
+			}
+		}
+		store.sweepSize = max(2*len(store.records), minSweepSize)
+	}
+	return record, previousFailedAt, nil
+}
+
+func (store *MemoryStore) Refund(ctx context.Context, key string, failedAt time.Time, previousFailedAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if record, ok := store.records[key]; ok && record.Failures > 0 {
+		record.Failures--
+		if record.LastFailedAt.Equal(failedAt) {
+			record.LastFailedAt = previousFailedAt
+		}
+		store.records[key] = record
+	}
+	return nil
+}
+
+func (store *MemoryStore) Get(ctx context.Context, key string) (Record, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.records[key], nil
+}
+
+func (store *MemoryStore) Reset(ctx context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.records, key)
+	return nil
+}
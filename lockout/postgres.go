@@ -0,0 +1,54 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+)
+
+// PostgresStore keeps the records in the login_attempts table
+type PostgresStore struct {
+	store db.Store
+}
+
+func NewPostgresStore(store db.Store) *PostgresStore {
+	return &PostgresStore{store: store}
+}
+
+func (store *PostgresStore) Fail(ctx context.Context, key string, failedAt time.Time, forgetBefore time.Time) (Record, time.Time, error) {
+	attempt, err := store.store.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+		Key:          key,
+		FailedAt:     failedAt,
+		ForgetBefore: forgetBefore,
+	})
+	if err != nil {
+		return Record{}, time.Time{}, err
+	}
+	return Record{Failures: attempt.Failures, LastFailedAt: attempt.LastFailedAt}, attempt.PreviousFailedAt, nil
+}
+
+func (store *PostgresStore) Refund(ctx context.Context, key string, failedAt time.Time, previousFailedAt time.Time) error {
+	return store.store.RefundLoginFailure(ctx, db.RefundLoginFailureParams{
+		Key:              key,
+		FailedAt:         failedAt,
+		PreviousFailedAt: previousFailedAt,
+	})
+}
+
+func (store *PostgresStore) Get(ctx context.Context, key string) (Record, error) {
+	attempt, err := store.store.GetLoginAttempt(ctx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Record{}, nil
+		}
+		return Record{}, err
+	}
+	return Record{Failures: attempt.Failures, LastFailedAt: attempt.LastFailedAt}, nil
+}
+
+func (store *PostgresStore) Reset(ctx context.Context, key string) error {
+	return store.store.DeleteLoginAttempt(ctx, key)
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer writes every message as an .eml file into a directory instead of sending it
+type FileMailer struct {
+	dir   string
+	from  string
+	count atomic.Int64
+}
+
+func NewFileMailer(dir string, from string) (*FileMailer, error) {
+	if len(dir) == 0 {
+		return nil, fmt.Errorf("file mailer requires MAIL_DIR")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (mailer *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405.000000000"), mailer.count.Add(1))
+	return os.WriteFile(filepath.Join(mailer.dir, name), format(mailer.from, msg, now), 0o644)
+}
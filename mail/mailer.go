@@ -0,0 +1,51 @@
+// Package mail sends emails to users through a pluggable Mailer
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lamdangtung/golang-sample-bank/util"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the mailer selected by config.Mailer: smtp sends through SMTP_HOST, file writes
+// every message to MAIL_DIR for local development and memory keeps them in the process.
+// An empty Mailer selects memory, so that tests never send real emails.
+func New(config util.Config) (Mailer, error) {
+	switch config.Mailer {
+	case "smtp":
+		if len(config.SMTPHost) == 0 || len(config.EmailSenderAddress) == 0 {
+			return nil, errors.New("smtp mailer requires SMTP_HOST and EMAIL_SENDER_ADDRESS")
+		}
+		return NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.EmailSenderAddress), nil
+	case "file":
+		return NewFileMailer(config.MailDir, config.EmailSenderAddress)
+	case "memory", "":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", config.Mailer)
+	}
+}
+
+func validate(msg Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("message has no recipient")
+	}
+	if len(msg.Subject) == 0 {
+		return errors.New("message has no subject")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	mailer, err := New(util.Config{})
+	require.NoError(t, err)
+	require.IsType(t, &MemoryMailer{}, mailer)
+
+	mailer, err = New(util.Config{Mailer: "file", MailDir: t.TempDir()})
+	require.NoError(t, err)
+	require.IsType(t, &FileMailer{}, mailer)
+
+	mailer, err = New(util.Config{Mailer: "smtp", SMTPHost: "localhost", SMTPPort: 25, EmailSenderAddress: "bank@example.com"})
+	require.NoError(t, err)
+	require.IsType(t, &SMTPMailer{}, mailer)
+
+	_, err = New(util.Config{Mailer: "smtp"})
+	require.Error(t, err)
+
+	_, err = New(util.Config{Mailer: "pigeon"})
+	require.Error(t, err)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	err := mailer.Send(context.Background(), Message{Subject: "no recipient"})
+	require.Error(t, err)
+
+	msg := Message{To: []string{util.RandomEmail()}, Subject: "hello", Body: "world"}
+	require.NoError(t, mailer.Send(context.Background(), msg))
+	require.Equal(t, []Message{msg}, mailer.Messages())
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "bank@example.com")
+	require.NoError(t, err)
+
+	msg := NewVerifyEmailMessage("alice@example.com", "Alice", "http://localhost/verify", time.Now())
+	require.NoError(t, mailer.Send(context.Background(), msg))
+	require.NoError(t, mailer.Send(context.Background(), msg))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	content := string(data)
+	require.True(t, strings.HasPrefix(content, "From: bank@example.com\r\nTo: alice@example.com\r\n"))
+	require.Contains(t, content, "\r\n\r\nHello Alice,\r\n")
+	require.Contains(t, content, "http://localhost/verify")
+}
+
+func TestVerifyEmailLink(t *testing.T) {
+	link := VerifyEmailLink("https://bank.example.com/", 42, "a+b/c")
+	require.Equal(t, "https://bank.example.com/users/verify-email?code=a%2Bb%2Fc&id=42", link)
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer keeps the messages it is given, tests read them back with Messages
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (mailer *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	mailer.messages = append(mailer.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far
+func (mailer *MemoryMailer) Messages() []Message {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	return append([]Message(nil), mailer.messages...)
+}
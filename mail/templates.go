@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VerifyEmailLink is the link that verifies an email, served by GET /users/verify-email
+func VerifyEmailLink(baseURL string, emailID int64, secretCode string) string {
+	query := url.Values{
+		"id":   {strconv.FormatInt(emailID, 10)},
+		"code": {secretCode},
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/users/verify-email?" + query.Encode()
+}
+
+// NewVerifyEmailMessage asks a new user to confirm their address before the link expires
+func NewVerifyEmailMessage(to string, fullName string, link string, expiredAt time.Time) Message {
+	return Message{
+		To:      []string{to},
+		Subject: "Welcome to Simple Bank, please verify your email",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Thank you for registering with Simple Bank. Please open the link below to verify your email address:\n\n"+
+			"%s\n\n"+
+			"The link expires at %s. You will not be able to make transfers until your email is verified.\n",
+			fullName, link, expiredAt.UTC().Format(time.RFC1123)),
+	}
+}
+
+// NewPasswordResetMessage sends the code that confirms a password reset through
+// POST /users/password-reset/confirm
+func NewPasswordResetMessage(to string, fullName string, resetID int64, secretCode string, expiredAt time.Time) Message {
+	return Message{
+		To:      []string{to},
+		Subject: "Reset your Simple Bank password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"We received a request to reset the password of your Simple Bank account. "+
+			"Use the following values to choose a new password:\n\n"+
+			"Reset ID: %d\n"+
+			"Reset code: %s\n\n"+
+			"The code expires at %s and can only be used once. Resetting your password signs you out of every device.\n\n"+
+			"If you did not ask for a new password, you can ignore this email.\n",
+			fullName, resetID, secretCode, expiredAt.UTC().Format(time.RFC1123)),
+	}
+}
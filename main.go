@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"log"
+	"net"
+	"net/http"
+	"os"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lamdangtung/golang-sample-bank/api"
 	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/docs/swagger"
+	"github.com/lamdangtung/golang-sample-bank/gapi"
+	"github.com/lamdangtung/golang-sample-bank/lockout"
+	"github.com/lamdangtung/golang-sample-bank/pb"
+	"github.com/lamdangtung/golang-sample-bank/reconcile"
 	"github.com/lamdangtung/golang-sample-bank/util"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func main() {
@@ -21,13 +36,160 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
-	server, err := api.NewServer(config, store)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			runReconcile(store, os.Args[2:])
+			return
+		case "grant-admin":
+			runGrantAdmin(store, os.Args[2:])
+			return
+		}
+	}
+
+	// one tracker for all servers, or each would allow the maximum failures of a memory store
+	loginLockout, err := lockout.New(config, store)
+	if err != nil {
+		log.Fatal("cannot create login lockout: ", err)
+	}
+
+	if config.IdempotencyKeyPurgeInterval > 0 {
+		go api.PurgeExpiredIdempotencyKeys(context.Background(), store, config.IdempotencyKeyPurgeInterval)
+	}
+
+	go runGinServer(config, store, loginLockout)
+	go runGatewayServer(config, store, loginLockout)
+	runGrpcServer(config, store, loginLockout)
+}
+
+func runGrpcServer(config util.Config, store db.Store, loginLockout *lockout.Tracker) {
+	server, err := gapi.NewServer(config, store, loginLockout)
+	if err != nil {
+		log.Fatal("cannot create gRPC server:", err)
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.UnaryAuthInterceptor()),
+		grpc.StreamInterceptor(server.StreamAuthInterceptor()),
+	)
+	pb.RegisterSimpleBankServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	listener, err := net.Listen("tcp", config.GRPCServerAddress)
+	if err != nil {
+		log.Fatal("cannot create listener:", err)
+	}
+
+	log.Printf("start gRPC server at %s", listener.Addr().String())
+	err = grpcServer.Serve(listener)
+	if err != nil {
+		log.Fatal("cannot start gRPC server:", err)
+	}
+}
+
+func runGatewayServer(config util.Config, store db.Store, loginLockout *lockout.Tracker) {
+	server, err := gapi.NewServer(config, store, loginLockout)
+	if err != nil {
+		log.Fatal("cannot create gRPC server:", err)
+	}
+
+	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	})
+	grpcMux := runtime.NewServeMux(jsonOption)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	if err != nil {
+		log.Fatal("cannot register handler server:", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", grpcMux)
+	mux.Handle("/swagger/", http.StripPrefix("/swagger/", http.FileServer(http.FS(swagger.FS))))
+
+	listener, err := net.Listen("tcp", config.HTTPGatewayAddress)
+	if err != nil {
+		log.Fatal("cannot create listener:", err)
+	}
+
+	log.Printf("start HTTP gateway server at %s", listener.Addr().String())
+	err = http.Serve(listener, mux)
+	if err != nil {
+		log.Fatal("cannot start HTTP gateway server:", err)
+	}
+}
+
+func runGinServer(config util.Config, store db.Store, loginLockout *lockout.Tracker) {
+	server, err := api.NewServer(config, store, loginLockout)
 	if err != nil {
 		log.Fatal("cannot create new server:", err)
 	}
-	err = server.Start(config.ServerAddress)
 
+	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("cannot start server:", err)
 	}
 }
+
+// runReconcile checks the ledger once and exits with status 1 when it found any issue,
+// so that it can run from cron: go run main.go reconcile [-json]
+func runReconcile(store db.Store, args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the report as JSON instead of a summary")
+	flags.Parse(args)
+
+	report, err := reconcile.Run(context.Background(), store)
+	if err != nil {
+		log.Fatal("cannot reconcile ledger: ", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.WriteSummary(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal("cannot write report: ", err)
+	}
+
+	if report.Issues() > 0 {
+		os.Exit(1)
+	}
+}
+
+// runGrantAdmin makes a user who already signed up an admin. Roles are otherwise only changed by
+// admins through the API, so this is how the first admin is appointed:
+// go run main.go grant-admin -username alice
+func runGrantAdmin(store db.Store, args []string) {
+	flags := flag.NewFlagSet("grant-admin", flag.ExitOnError)
+	username := flags.String("username", "", "the user to make an admin")
+	flags.Parse(args)
+
+	if len(*username) == 0 {
+		log.Fatal("missing -username")
+	}
+
+	user, err := store.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+		Username: *username,
+		Role:     db.UserRoleAdmin,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Fatalf("user %s not found, they need to sign up first", *username)
+		}
+		log.Fatal("cannot grant admin role: ", err)
+	}
+
+	log.Printf("user %s is now an admin", user.Username)
+}
@@ -0,0 +1,204 @@
+// Package mfa enrolls users in TOTP (RFC 6238) two-factor authentication and checks their codes
+package mfa
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+var (
+	ErrAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrNotEnrolled    = errors.New("no pending two-factor enrollment, enroll first")
+	ErrInvalidCode    = errors.New("invalid or already used two-factor code")
+	ErrCodeRequired   = errors.New("a fresh two-factor code is required for this transfer")
+)
+
+// Enrollment is shown once to the user, who adds the secret to their authenticator app
+type Enrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// Service manages the two-factor authentication of users stored in the database
+type Service struct {
+	store      db.Store
+	issuer     string
+	thresholds TransferThresholds
+	now        func() time.Time
+}
+
+// NewService creates a new two-factor authentication service. The issuer is the name
+// authenticator apps show next to the codes.
+func NewService(store db.Store, issuer string, thresholds TransferThresholds) *Service {
+	return &Service{
+		store:      store,
+		issuer:     issuer,
+		thresholds: thresholds,
+		now:        time.Now,
+	}
+}
+
+// Enroll generates a new secret for the user. Two-factor authentication is only enabled once
+// a code of the secret is confirmed, enrolling again before that replaces the secret.
+func (service *Service) Enroll(ctx context.Context, username string) (Enrollment, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	_, err = service.store.UpsertPendingUserTotp(ctx, db.UpsertPendingUserTotpParams{
+		Username: username,
+		Secret:   secret,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Enrollment{}, ErrAlreadyEnabled
+		}
+		return Enrollment{}, err
+	}
+
+	return Enrollment{
+		Secret:          secret,
+		ProvisioningURI: ProvisioningURI(service.issuer, username, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication with a code of the enrolled secret and returns
+// recovery codes. Only their hashes are kept, so they cannot be shown again.
+func (service *Service) Confirm(ctx context.Context, username string, code string) ([]string, error) {
+	totp, err := service.store.GetUserTotp(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotEnrolled
+		}
+		return nil, err
+	}
+	if totp.ConfirmedAt.Valid {
+		return nil, ErrAlreadyEnabled
+	}
+
+	step, ok := matchCode(totp.Secret, code, service.now())
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	codes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.store.ConfirmTOTPTx(ctx, db.ConfirmTOTPTxParams{
+		Username:            username,
+		Step:                step,
+		HashedRecoveryCodes: hashedCodes,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTOTPNotPending) {
+			return nil, ErrAlreadyEnabled
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off, it takes a code or a recovery code
+func (service *Service) Disable(ctx context.Context, username string, code string) error {
+	if err := service.VerifyOrRecover(ctx, username, code); err != nil {
+		return err
+	}
+	return service.store.DisableTOTPTx(ctx, username)
+}
+
+// Enabled reports whether the user confirmed their two-factor enrollment
+func (service *Service) Enabled(ctx context.Context, username string) (bool, error) {
+	totp, err := service.store.GetUserTotp(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return totp.ConfirmedAt.Valid, nil
+}
+
+// Verify accepts a TOTP code once. A code is rejected when a code of the same or a later
+// time step was already used, so an intercepted code cannot be replayed.
+func (service *Service) Verify(ctx context.Context, username string, code string) error {
+	totp, err := service.enabledTotp(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	step, ok := matchCode(totp.Secret, code, service.now())
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	_, err = service.store.UseUserTotpStep(ctx, db.UseUserTotpStepParams{
+		Username: username,
+		Step:     step,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidCode
+	}
+	return err
+}
+
+// VerifyOrRecover accepts a TOTP code like Verify, or an unused recovery code for users
+// who lost their authenticator
+func (service *Service) VerifyOrRecover(ctx context.Context, username string, code string) error {
+	if isTOTPCode(code) {
+		return service.Verify(ctx, username, code)
+	}
+
+	if _, err := service.enabledTotp(ctx, username); err != nil {
+		return err
+	}
+
+	_, err := service.store.UseTotpRecoveryCode(ctx, db.UseTotpRecoveryCodeParams{
+		Username:   username,
+		HashedCode: hashRecoveryCode(code),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidCode
+	}
+	return err
+}
+
+// CheckTransfer demands a fresh TOTP code for transfers from the configured thresholds.
+// Users without two-factor authentication are not asked for one.
+func (service *Service) CheckTransfer(ctx context.Context, username string, amount money.Amount, code string) error {
+	if !service.thresholds.Requires(amount) {
+		return nil
+	}
+
+	enabled, err := service.Enabled(ctx, username)
+	if err != nil || !enabled {
+		return err
+	}
+
+	if code == "" {
+		return ErrCodeRequired
+	}
+	return service.Verify(ctx, username, code)
+}
+
+func (service *Service) enabledTotp(ctx context.Context, username string) (db.UserTotp, error) {
+	totp, err := service.store.GetUserTotp(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return totp, ErrNotEnabled
+		}
+		return totp, err
+	}
+	if !totp.ConfirmedAt.Valid {
+		return totp, ErrNotEnabled
+	}
+	return totp, nil
+}
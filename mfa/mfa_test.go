@@ -0,0 +1,233 @@
+package mfa
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/lamdangtung/golang-sample-bank/db/mock"
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHOTPVectors(t *testing.T) {
+	// test vectors of RFC 6238 appendix B for SHA1
+	key := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, expected := range vectors {
+		require.Equal(t, expected, hotp(key, uint64(timeStep(time.Unix(unix, 0))), 8), "time %d", unix)
+	}
+}
+
+func TestMatchCode(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+	require.Len(t, code, totpDigits)
+
+	step, ok := matchCode(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, timeStep(now), step)
+
+	// one step of clock drift is accepted, two are not
+	_, ok = matchCode(secret, code, now.Add(totpPeriod*time.Second))
+	require.True(t, ok)
+	_, ok = matchCode(secret, code, now.Add(2*totpPeriod*time.Second))
+	require.False(t, ok)
+
+	_, ok = matchCode(secret, "12345", now)
+	require.False(t, ok)
+	_, ok = matchCode(secret, "abcdef", now)
+	require.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Simple Bank", "alice", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/Simple%20Bank:alice?algorithm=SHA1&digits=6&issuer=Simple+Bank&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashedCodes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, hashedCodes, recoveryCodeCount)
+
+	for i, code := range codes {
+		require.Len(t, code, recoveryCodeSize+1)
+		require.Equal(t, hashedCodes[i], hashRecoveryCode(code))
+		require.Equal(t, hashedCodes[i], hashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(code, "-", ""))))
+	}
+}
+
+func TestParseTransferThresholds(t *testing.T) {
+	thresholds, err := ParseTransferThresholds("USD:10000, eur:5000.50,VND:250000000")
+	require.NoError(t, err)
+	require.Len(t, thresholds, 3)
+	require.Equal(t, int64(500050), thresholds[money.EUR].Units())
+
+	require.True(t, thresholds.Requires(money.New(1000000, money.USD)))
+	require.False(t, thresholds.Requires(money.New(999999, money.USD)))
+	require.False(t, thresholds.Requires(money.New(999999999, "GBP")))
+
+	thresholds, err = ParseTransferThresholds("")
+	require.NoError(t, err)
+	require.False(t, thresholds.Requires(money.New(999999999, money.USD)))
+
+	for _, s := range []string{"USD", "USD:abc", "XXX:10", "USD:0"} {
+		_, err = ParseTransferThresholds(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+
+	enabled := db.UserTotp{
+		Username:    "alice",
+		Secret:      secret,
+		ConfirmedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+	}
+
+	testCases := []struct {
+		name       string
+		code       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq("alice")).Times(1).Return(enabled, nil)
+				store.EXPECT().
+					UseUserTotpStep(gomock.Any(), gomock.Eq(db.UseUserTotpStepParams{Username: "alice", Step: timeStep(now)})).
+					Times(1).
+					Return(enabled, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Replayed",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(1).Return(db.UserTotp{}, sql.ErrNoRows)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+		{
+			name: "WrongCode",
+			code: wrongCode(code),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+		{
+			name: "PendingEnrollment",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(db.UserTotp{Secret: secret}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrNotEnabled)
+			},
+		},
+		{
+			name: "RecoveryCode",
+			code: "abcde-fghij",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+				store.EXPECT().
+					UseTotpRecoveryCode(gomock.Any(), gomock.Eq(db.UseTotpRecoveryCodeParams{
+						Username:   "alice",
+						HashedCode: hashRecoveryCode("abcdefghij"),
+					})).
+					Times(1).
+					Return(db.TotpRecoveryCode{}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UsedRecoveryCode",
+			code: "abcde-fghij",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTotp(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+				store.EXPECT().UseTotpRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(db.TotpRecoveryCode{}, sql.ErrNoRows)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCode)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			service := NewService(store, "Simple Bank", nil)
+			service.now = func() time.Time { return now }
+			tc.checkError(t, service.VerifyOrRecover(context.Background(), "alice", tc.code))
+		})
+	}
+}
+
+func TestCheckTransfer(t *testing.T) {
+	thresholds, err := ParseTransferThresholds("USD:1000")
+	require.NoError(t, err)
+	small := money.New(99999, money.USD)
+	large := money.New(100000, money.USD)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	service := NewService(store, "Simple Bank", thresholds)
+
+	// below the threshold the store is not even read
+	require.NoError(t, service.CheckTransfer(context.Background(), "alice", small, ""))
+
+	store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq("bob")).Times(1).Return(db.UserTotp{}, sql.ErrNoRows)
+	require.NoError(t, service.CheckTransfer(context.Background(), "bob", large, ""))
+
+	enabled := db.UserTotp{Username: "alice", ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	store.EXPECT().GetUserTotp(gomock.Any(), gomock.Eq("alice")).Times(1).Return(enabled, nil)
+	require.ErrorIs(t, service.CheckTransfer(context.Background(), "alice", large, ""), ErrCodeRequired)
+}
+
+// wrongCode changes every digit of a code, so that it matches none of the nearby steps by chance
+func wrongCode(code string) string {
+	digits := []byte(code)
+	for i := range digits {
+		digits[i] = '0' + (digits[i]-'0'+5)%10
+	}
+	return string(digits)
+}
@@ -0,0 +1,38 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeSize is the number of base32 characters of a code, 50 random bits
+	recoveryCodeSize = 10
+)
+
+// generateRecoveryCodes returns codes formatted like "abcde-fghij" and the hashes stored for them
+func generateRecoveryCodes() (codes []string, hashedCodes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32NoPadding.EncodeToString(random)[:recoveryCodeSize])
+		code = code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:]
+		codes = append(codes, code)
+		hashedCodes = append(hashedCodes, hashRecoveryCode(code))
+	}
+	return codes, hashedCodes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes, so that codes can be typed as they are read.
+// Recovery codes are random enough for a plain SHA-256 hash.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package mfa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+// TransferThresholds are the amounts, by currency, from which a transfer needs a fresh TOTP code
+type TransferThresholds map[string]money.Amount
+
+// ParseTransferThresholds parses a list such as "USD:10000,EUR:10000,VND:250000000"
+// where amounts are in major units. An empty list never requires a code.
+func ParseTransferThresholds(s string) (TransferThresholds, error) {
+	thresholds := TransferThresholds{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		code, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid transfer threshold %q: must be CURRENCY:AMOUNT", item)
+		}
+		amount, err := money.Parse(strings.TrimSpace(value), strings.ToUpper(strings.TrimSpace(code)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer threshold %q: %w", item, err)
+		}
+		if !amount.IsPositive() {
+			return nil, fmt.Errorf("invalid transfer threshold %q: must be positive", item)
+		}
+		thresholds[amount.Currency().Code] = amount
+	}
+	return thresholds, nil
+}
+
+// Requires reports whether a transfer of the amount needs a TOTP code
+func (thresholds TransferThresholds) Requires(amount money.Amount) bool {
+	threshold, ok := thresholds[amount.Currency().Code]
+	return ok && amount.Units() >= threshold.Units()
+}
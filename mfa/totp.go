@@ -0,0 +1,112 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TOTP parameters of RFC 6238, they are the defaults of authenticator apps
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew also accepts the codes of the previous and the next step, for clocks that drift
+	totpSkew = 1
+	// secretSize is the length of the HMAC-SHA1 key recommended by RFC 4226
+	secretSize = 20
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 TOTP secret
+func GenerateSecret() (string, error) {
+	key := make([]byte, secretSize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base32NoPadding.EncodeToString(key), nil
+}
+
+// ProvisioningURI is the otpauth URI that authenticator apps read from a QR code
+func ProvisioningURI(issuer string, accountName string, secret string) string {
+	query := url.Values{
+		"secret":    {secret},
+		"issuer":    {issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {strconv.Itoa(totpDigits)},
+		"period":    {strconv.Itoa(totpPeriod)},
+	}
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+accountName) + "?" + query.Encode()
+}
+
+// timeStep is the number of periods elapsed since the Unix epoch
+func timeStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// hotp computes the RFC 4226 one-time password of a counter
+func hotp(key []byte, counter uint64, digits int) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
+
+// GenerateCode returns the code of a secret at the given time
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(timeStep(t)), totpDigits), nil
+}
+
+// matchCode returns the time step of a code that is valid for the secret around the given time
+func matchCode(secret string, code string, t time.Time) (int64, bool) {
+	if !isTOTPCode(code) {
+		return 0, false
+	}
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := timeStep(t)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected := hotp(key, uint64(step), totpDigits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
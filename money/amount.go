@@ -0,0 +1,195 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrOverflow         = errors.New("amount out of range")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// Amount is an amount of money counted in minor units of its currency, e.g. cents for USD
+type Amount struct {
+	units    int64
+	currency Currency
+}
+
+// New creates an amount from minor units.
+// Codes missing from the registry are kept as is and treated as having no minor unit.
+func New(units int64, code string) Amount {
+	currency, err := Lookup(code)
+	if err != nil {
+		currency = Currency{Code: code}
+	}
+	return Amount{units: units, currency: currency}
+}
+
+// Units returns the amount in minor units
+func (amount Amount) Units() int64 {
+	return amount.units
+}
+
+// Currency returns the currency of the amount
+func (amount Amount) Currency() Currency {
+	return amount.currency
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (amount Amount) IsPositive() bool {
+	return amount.units > 0
+}
+
+// IsNegative reports whether the amount is less than zero
+func (amount Amount) IsNegative() bool {
+	return amount.units < 0
+}
+
+// Add returns amount + other. Both amounts must be in the same currency.
+func (amount Amount) Add(other Amount) (Amount, error) {
+	if err := amount.checkCurrency(other); err != nil {
+		return Amount{}, err
+	}
+	if (other.units > 0 && amount.units > math.MaxInt64-other.units) ||
+		(other.units < 0 && amount.units < math.MinInt64-other.units) {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{units: amount.units + other.units, currency: amount.currency}, nil
+}
+
+// Sub returns amount - other. Both amounts must be in the same currency.
+func (amount Amount) Sub(other Amount) (Amount, error) {
+	negated, err := other.Neg()
+	if err != nil {
+		return Amount{}, err
+	}
+	return amount.Add(negated)
+}
+
+// Neg returns -amount
+func (amount Amount) Neg() (Amount, error) {
+	if amount.units == math.MinInt64 {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{units: -amount.units, currency: amount.currency}, nil
+}
+
+// Mul returns amount * n
+func (amount Amount) Mul(n int64) (Amount, error) {
+	if amount.units == 0 || n == 0 {
+		return Amount{currency: amount.currency}, nil
+	}
+	result := amount.units * n
+	if result/n != amount.units || (amount.units == -1 && n == math.MinInt64) || (n == -1 && amount.units == math.MinInt64) {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{units: result, currency: amount.currency}, nil
+}
+
+func (amount Amount) checkCurrency(other Amount) error {
+	if amount.currency.Code != other.currency.Code {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, amount.currency.Code, other.currency.Code)
+	}
+	return nil
+}
+
+// Parse parses a decimal string such as "12.34" into an amount of the given currency.
+// The string may not have more decimals than the minor unit of the currency.
+func Parse(s string, code string) (Amount, error) {
+	currency, err := Lookup(code)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	digits := strings.TrimPrefix(s, "-")
+	sign := s[:len(s)-len(digits)]
+
+	integer, fraction, hasFraction := strings.Cut(digits, ".")
+	if !isDigits(integer) || (hasFraction && !isDigits(fraction)) {
+		return Amount{}, fmt.Errorf("%w %q: must be a decimal number", ErrInvalidAmount, s)
+	}
+	if len(fraction) > currency.Exponent {
+		return Amount{}, fmt.Errorf("%w %q: %s allows at most %d decimals", ErrInvalidAmount, s, currency.Code, currency.Exponent)
+	}
+
+	fraction += strings.Repeat("0", currency.Exponent-len(fraction))
+	units, err := strconv.ParseInt(sign+integer+fraction, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w %q: %w", ErrInvalidAmount, s, ErrOverflow)
+	}
+	return Amount{units: units, currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a plain decimal string such as "-12.34", the format accepted by Parse
+func (amount Amount) String() string {
+	sign, integer, fraction := amount.parts()
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
+
+// Display formats the amount for humans, with grouped thousands and the currency symbol, e.g. "$1,234.56"
+func (amount Amount) Display() string {
+	sign, integer, fraction := amount.parts()
+
+	var b strings.Builder
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	number := b.String()
+	if fraction != "" {
+		number += "." + fraction
+	}
+
+	currency := amount.currency
+	switch {
+	case currency.Symbol == "":
+		return sign + number + " " + currency.Code
+	case currency.SymbolAfter:
+		return sign + number + " " + currency.Symbol
+	default:
+		return sign + currency.Symbol + number
+	}
+}
+
+func (amount Amount) parts() (sign string, integer string, fraction string) {
+	units := uint64(amount.units)
+	if amount.units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	digits := strconv.FormatUint(units, 10)
+	exponent := amount.currency.Exponent
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign, digits[:len(digits)-exponent], digits[len(digits)-exponent:]
+}
+
+// MarshalJSON encodes the amount as a decimal string so that no precision is lost in clients
+func (amount Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amount.String())
+}
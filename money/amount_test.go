@@ -0,0 +1,122 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	currency, err := Lookup(VND)
+	require.NoError(t, err)
+	require.Equal(t, 0, currency.Exponent)
+
+	currency, err = Lookup("KWD")
+	require.NoError(t, err)
+	require.Equal(t, 3, currency.Exponent)
+
+	_, err = Lookup("XYZ")
+	require.True(t, errors.Is(err, ErrUnknownCurrency))
+	require.False(t, IsSupported("usd"))
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		currency string
+		units    int64
+	}{
+		{"12.34", USD, 1234},
+		{"12.3", USD, 1230},
+		{"12", USD, 1200},
+		{"-0.05", EUR, -5},
+		{"10000", VND, 10000},
+		{"1.005", "KWD", 1005},
+		{"92233720368547758.07", USD, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		amount, err := Parse(tc.input, tc.currency)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.units, amount.Units(), tc.input)
+		require.Equal(t, tc.currency, amount.Currency().Code)
+	}
+
+	for _, invalid := range []string{"", "-", "1.", ".5", "1.234", "1,000", "1e3", "+1", " 1", "92233720368547758.08"} {
+		_, err := Parse(invalid, USD)
+		require.True(t, errors.Is(err, ErrInvalidAmount), invalid)
+	}
+
+	_, err := Parse("1.5", VND)
+	require.True(t, errors.Is(err, ErrInvalidAmount))
+
+	_, err = Parse("1", "XYZ")
+	require.True(t, errors.Is(err, ErrUnknownCurrency))
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		amount  Amount
+		str     string
+		display string
+	}{
+		{New(1234, USD), "12.34", "$12.34"},
+		{New(5, USD), "0.05", "$0.05"},
+		{New(-123456789, EUR), "-1234567.89", "-€1,234,567.89"},
+		{New(10000, VND), "10000", "10,000 ₫"},
+		{New(1005, "KWD"), "1.005", "1.005 KWD"},
+		{New(math.MinInt64, USD), "-92233720368547758.08", "-$92,233,720,368,547,758.08"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.str, tc.amount.String())
+		require.Equal(t, tc.display, tc.amount.Display())
+
+		parsed, err := Parse(tc.str, tc.amount.Currency().Code)
+		require.NoError(t, err)
+		require.Equal(t, tc.amount, parsed)
+	}
+
+	data, err := json.Marshal(New(1234, USD))
+	require.NoError(t, err)
+	require.JSONEq(t, `"12.34"`, string(data))
+}
+
+func TestArithmetic(t *testing.T) {
+	a := New(1000, USD)
+	b := New(250, USD)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, int64(1250), sum.Units())
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, int64(-750), diff.Units())
+	require.True(t, diff.IsNegative())
+
+	product, err := b.Mul(-3)
+	require.NoError(t, err)
+	require.Equal(t, int64(-750), product.Units())
+
+	_, err = a.Add(New(1, EUR))
+	require.True(t, errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = New(math.MaxInt64, USD).Add(New(1, USD))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = New(math.MinInt64, USD).Sub(New(1, USD))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = New(math.MinInt64, USD).Neg()
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = New(math.MaxInt64/2+1, USD).Mul(2)
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = New(-1, USD).Mul(math.MinInt64)
+	require.True(t, errors.Is(err, ErrOverflow))
+}
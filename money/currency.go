@@ -0,0 +1,48 @@
+// Package money represents amounts of money in the minor unit of their ISO 4217 currency
+package money
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Currency codes used throughout the application
+const (
+	USD = "USD"
+	EUR = "EUR"
+	VND = "VND"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency describes an ISO 4217 currency
+type Currency struct {
+	Code    string
+	Numeric string
+	Name    string
+	// Exponent is the number of decimals of the minor unit, e.g. 2 for USD (cents) and 0 for VND
+	Exponent int
+	// Symbol is used when displaying amounts; the code is used when it is empty
+	Symbol string
+	// SymbolAfter puts the symbol after the number, e.g. "10,000 ₫"
+	SymbolAfter bool
+}
+
+// Lookup returns the currency registered for an ISO 4217 code
+func Lookup(code string) (Currency, error) {
+	currency, ok := registry[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+// IsSupported reports whether the code is a registered ISO 4217 currency
+func IsSupported(code string) bool {
+	_, ok := registry[code]
+	return ok
+}
+
+func (currency Currency) String() string {
+	return currency.Code
+}
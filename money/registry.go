@@ -0,0 +1,174 @@
+package money
+
+// registry holds the active ISO 4217 currencies. Funds, precious metals and testing codes
+// that have no minor unit (XAU, XDR, XTS, ...) are deliberately left out.
+var registry = map[string]Currency{
+	"AED": {Code: "AED", Numeric: "784", Name: "UAE Dirham", Exponent: 2},
+	"AFN": {Code: "AFN", Numeric: "971", Name: "Afghani", Exponent: 2},
+	"ALL": {Code: "ALL", Numeric: "008", Name: "Lek", Exponent: 2},
+	"AMD": {Code: "AMD", Numeric: "051", Name: "Armenian Dram", Exponent: 2},
+	"ANG": {Code: "ANG", Numeric: "532", Name: "Netherlands Antillean Guilder", Exponent: 2},
+	"AOA": {Code: "AOA", Numeric: "973", Name: "Kwanza", Exponent: 2},
+	"ARS": {Code: "ARS", Numeric: "032", Name: "Argentine Peso", Exponent: 2},
+	"AUD": {Code: "AUD", Numeric: "036", Name: "Australian Dollar", Exponent: 2},
+	"AWG": {Code: "AWG", Numeric: "533", Name: "Aruban Florin", Exponent: 2},
+	"AZN": {Code: "AZN", Numeric: "944", Name: "Azerbaijan Manat", Exponent: 2},
+	"BAM": {Code: "BAM", Numeric: "977", Name: "Convertible Mark", Exponent: 2},
+	"BBD": {Code: "BBD", Numeric: "052", Name: "Barbados Dollar", Exponent: 2},
+	"BDT": {Code: "BDT", Numeric: "050", Name: "Taka", Exponent: 2},
+	"BGN": {Code: "BGN", Numeric: "975", Name: "Bulgarian Lev", Exponent: 2},
+	"BHD": {Code: "BHD", Numeric: "048", Name: "Bahraini Dinar", Exponent: 3},
+	"BIF": {Code: "BIF", Numeric: "108", Name: "Burundi Franc", Exponent: 0},
+	"BMD": {Code: "BMD", Numeric: "060", Name: "Bermudian Dollar", Exponent: 2},
+	"BND": {Code: "BND", Numeric: "096", Name: "Brunei Dollar", Exponent: 2},
+	"BOB": {Code: "BOB", Numeric: "068", Name: "Boliviano", Exponent: 2},
+	"BOV": {Code: "BOV", Numeric: "984", Name: "Mvdol", Exponent: 2},
+	"BRL": {Code: "BRL", Numeric: "986", Name: "Brazilian Real", Exponent: 2},
+	"BSD": {Code: "BSD", Numeric: "044", Name: "Bahamian Dollar", Exponent: 2},
+	"BTN": {Code: "BTN", Numeric: "064", Name: "Ngultrum", Exponent: 2},
+	"BWP": {Code: "BWP", Numeric: "072", Name: "Pula", Exponent: 2},
+	"BYN": {Code: "BYN", Numeric: "933", Name: "Belarusian Ruble", Exponent: 2},
+	"BZD": {Code: "BZD", Numeric: "084", Name: "Belize Dollar", Exponent: 2},
+	"CAD": {Code: "CAD", Numeric: "124", Name: "Canadian Dollar", Exponent: 2},
+	"CDF": {Code: "CDF", Numeric: "976", Name: "Congolese Franc", Exponent: 2},
+	"CHE": {Code: "CHE", Numeric: "947", Name: "WIR Euro", Exponent: 2},
+	"CHF": {Code: "CHF", Numeric: "756", Name: "Swiss Franc", Exponent: 2},
+	"CHW": {Code: "CHW", Numeric: "948", Name: "WIR Franc", Exponent: 2},
+	"CLF": {Code: "CLF", Numeric: "990", Name: "Unidad de Fomento", Exponent: 4},
+	"CLP": {Code: "CLP", Numeric: "152", Name: "Chilean Peso", Exponent: 0},
+	"CNY": {Code: "CNY", Numeric: "156", Name: "Yuan Renminbi", Exponent: 2, Symbol: "¥"},
+	"COP": {Code: "COP", Numeric: "170", Name: "Colombian Peso", Exponent: 2},
+	"COU": {Code: "COU", Numeric: "970", Name: "Unidad de Valor Real", Exponent: 2},
+	"CRC": {Code: "CRC", Numeric: "188", Name: "Costa Rican Colon", Exponent: 2},
+	"CUC": {Code: "CUC", Numeric: "931", Name: "Peso Convertible", Exponent: 2},
+	"CUP": {Code: "CUP", Numeric: "192", Name: "Cuban Peso", Exponent: 2},
+	"CVE": {Code: "CVE", Numeric: "132", Name: "Cabo Verde Escudo", Exponent: 2},
+	"CZK": {Code: "CZK", Numeric: "203", Name: "Czech Koruna", Exponent: 2},
+	"DJF": {Code: "DJF", Numeric: "262", Name: "Djibouti Franc", Exponent: 0},
+	"DKK": {Code: "DKK", Numeric: "208", Name: "Danish Krone", Exponent: 2},
+	"DOP": {Code: "DOP", Numeric: "214", Name: "Dominican Peso", Exponent: 2},
+	"DZD": {Code: "DZD", Numeric: "012", Name: "Algerian Dinar", Exponent: 2},
+	"EGP": {Code: "EGP", Numeric: "818", Name: "Egyptian Pound", Exponent: 2},
+	"ERN": {Code: "ERN", Numeric: "232", Name: "Nakfa", Exponent: 2},
+	"ETB": {Code: "ETB", Numeric: "230", Name: "Ethiopian Birr", Exponent: 2},
+	"EUR": {Code: "EUR", Numeric: "978", Name: "Euro", Exponent: 2, Symbol: "€"},
+	"FJD": {Code: "FJD", Numeric: "242", Name: "Fiji Dollar", Exponent: 2},
+	"FKP": {Code: "FKP", Numeric: "238", Name: "Falkland Islands Pound", Exponent: 2},
+	"GBP": {Code: "GBP", Numeric: "826", Name: "Pound Sterling", Exponent: 2, Symbol: "£"},
+	"GEL": {Code: "GEL", Numeric: "981", Name: "Lari", Exponent: 2},
+	"GHS": {Code: "GHS", Numeric: "936", Name: "Ghana Cedi", Exponent: 2},
+	"GIP": {Code: "GIP", Numeric: "292", Name: "Gibraltar Pound", Exponent: 2},
+	"GMD": {Code: "GMD", Numeric: "270", Name: "Dalasi", Exponent: 2},
+	"GNF": {Code: "GNF", Numeric: "324", Name: "Guinean Franc", Exponent: 0},
+	"GTQ": {Code: "GTQ", Numeric: "320", Name: "Quetzal", Exponent: 2},
+	"GYD": {Code: "GYD", Numeric: "328", Name: "Guyana Dollar", Exponent: 2},
+	"HKD": {Code: "HKD", Numeric: "344", Name: "Hong Kong Dollar", Exponent: 2},
+	"HNL": {Code: "HNL", Numeric: "340", Name: "Lempira", Exponent: 2},
+	"HRK": {Code: "HRK", Numeric: "191", Name: "Kuna", Exponent: 2},
+	"HTG": {Code: "HTG", Numeric: "332", Name: "Gourde", Exponent: 2},
+	"HUF": {Code: "HUF", Numeric: "348", Name: "Forint", Exponent: 2},
+	"IDR": {Code: "IDR", Numeric: "360", Name: "Rupiah", Exponent: 2},
+	"ILS": {Code: "ILS", Numeric: "376", Name: "New Israeli Sheqel", Exponent: 2, Symbol: "₪"},
+	"INR": {Code: "INR", Numeric: "356", Name: "Indian Rupee", Exponent: 2, Symbol: "₹"},
+	"IQD": {Code: "IQD", Numeric: "368", Name: "Iraqi Dinar", Exponent: 3},
+	"IRR": {Code: "IRR", Numeric: "364", Name: "Iranian Rial", Exponent: 2},
+	"ISK": {Code: "ISK", Numeric: "352", Name: "Iceland Krona", Exponent: 0},
+	"JMD": {Code: "JMD", Numeric: "388", Name: "Jamaican Dollar", Exponent: 2},
+	"JOD": {Code: "JOD", Numeric: "400", Name: "Jordanian Dinar", Exponent: 3},
+	"JPY": {Code: "JPY", Numeric: "392", Name: "Yen", Exponent: 0, Symbol: "¥"},
+	"KES": {Code: "KES", Numeric: "404", Name: "Kenyan Shilling", Exponent: 2},
+	"KGS": {Code: "KGS", Numeric: "417", Name: "Som", Exponent: 2},
+	"KHR": {Code: "KHR", Numeric: "116", Name: "Riel", Exponent: 2},
+	"KMF": {Code: "KMF", Numeric: "174", Name: "Comorian Franc", Exponent: 0},
+	"KPW": {Code: "KPW", Numeric: "408", Name: "North Korean Won", Exponent: 2},
+	"KRW": {Code: "KRW", Numeric: "410", Name: "Won", Exponent: 0, Symbol: "₩"},
+	"KWD": {Code: "KWD", Numeric: "414", Name: "Kuwaiti Dinar", Exponent: 3},
+	"KYD": {Code: "KYD", Numeric: "136", Name: "Cayman Islands Dollar", Exponent: 2},
+	"KZT": {Code: "KZT", Numeric: "398", Name: "Tenge", Exponent: 2},
+	"LAK": {Code: "LAK", Numeric: "418", Name: "Lao Kip", Exponent: 2},
+	"LBP": {Code: "LBP", Numeric: "422", Name: "Lebanese Pound", Exponent: 2},
+	"LKR": {Code: "LKR", Numeric: "144", Name: "Sri Lanka Rupee", Exponent: 2},
+	"LRD": {Code: "LRD", Numeric: "430", Name: "Liberian Dollar", Exponent: 2},
+	"LSL": {Code: "LSL", Numeric: "426", Name: "Loti", Exponent: 2},
+	"LYD": {Code: "LYD", Numeric: "434", Name: "Libyan Dinar", Exponent: 3},
+	"MAD": {Code: "MAD", Numeric: "504", Name: "Moroccan Dirham", Exponent: 2},
+	"MDL": {Code: "MDL", Numeric: "498", Name: "Moldovan Leu", Exponent: 2},
+	"MGA": {Code: "MGA", Numeric: "969", Name: "Malagasy Ariary", Exponent: 2},
+	"MKD": {Code: "MKD", Numeric: "807", Name: "Denar", Exponent: 2},
+	"MMK": {Code: "MMK", Numeric: "104", Name: "Kyat", Exponent: 2},
+	"MNT": {Code: "MNT", Numeric: "496", Name: "Tugrik", Exponent: 2},
+	"MOP": {Code: "MOP", Numeric: "446", Name: "Pataca", Exponent: 2},
+	"MRU": {Code: "MRU", Numeric: "929", Name: "Ouguiya", Exponent: 2},
+	"MUR": {Code: "MUR", Numeric: "480", Name: "Mauritius Rupee", Exponent: 2},
+	"MVR": {Code: "MVR", Numeric: "462", Name: "Rufiyaa", Exponent: 2},
+	"MWK": {Code: "MWK", Numeric: "454", Name: "Malawi Kwacha", Exponent: 2},
+	"MXN": {Code: "MXN", Numeric: "484", Name: "Mexican Peso", Exponent: 2},
+	"MXV": {Code: "MXV", Numeric: "979", Name: "Mexican Unidad de Inversion (UDI)", Exponent: 2},
+	"MYR": {Code: "MYR", Numeric: "458", Name: "Malaysian Ringgit", Exponent: 2},
+	"MZN": {Code: "MZN", Numeric: "943", Name: "Mozambique Metical", Exponent: 2},
+	"NAD": {Code: "NAD", Numeric: "516", Name: "Namibia Dollar", Exponent: 2},
+	"NGN": {Code: "NGN", Numeric: "566", Name: "Naira", Exponent: 2, Symbol: "₦"},
+	"NIO": {Code: "NIO", Numeric: "558", Name: "Cordoba Oro", Exponent: 2},
+	"NOK": {Code: "NOK", Numeric: "578", Name: "Norwegian Krone", Exponent: 2},
+	"NPR": {Code: "NPR", Numeric: "524", Name: "Nepalese Rupee", Exponent: 2},
+	"NZD": {Code: "NZD", Numeric: "554", Name: "New Zealand Dollar", Exponent: 2},
+	"OMR": {Code: "OMR", Numeric: "512", Name: "Rial Omani", Exponent: 3},
+	"PAB": {Code: "PAB", Numeric: "590", Name: "Balboa", Exponent: 2},
+	"PEN": {Code: "PEN", Numeric: "604", Name: "Sol", Exponent: 2},
+	"PGK": {Code: "PGK", Numeric: "598", Name: "Kina", Exponent: 2},
+	"PHP": {Code: "PHP", Numeric: "608", Name: "Philippine Peso", Exponent: 2, Symbol: "₱"},
+	"PKR": {Code: "PKR", Numeric: "586", Name: "Pakistan Rupee", Exponent: 2},
+	"PLN": {Code: "PLN", Numeric: "985", Name: "Zloty", Exponent: 2},
+	"PYG": {Code: "PYG", Numeric: "600", Name: "Guarani", Exponent: 0},
+	"QAR": {Code: "QAR", Numeric: "634", Name: "Qatari Rial", Exponent: 2},
+	"RON": {Code: "RON", Numeric: "946", Name: "Romanian Leu", Exponent: 2},
+	"RSD": {Code: "RSD", Numeric: "941", Name: "Serbian Dinar", Exponent: 2},
+	"RUB": {Code: "RUB", Numeric: "643", Name: "Russian Ruble", Exponent: 2, Symbol: "₽"},
+	"RWF": {Code: "RWF", Numeric: "646", Name: "Rwanda Franc", Exponent: 0},
+	"SAR": {Code: "SAR", Numeric: "682", Name: "Saudi Riyal", Exponent: 2},
+	"SBD": {Code: "SBD", Numeric: "090", Name: "Solomon Islands Dollar", Exponent: 2},
+	"SCR": {Code: "SCR", Numeric: "690", Name: "Seychelles Rupee", Exponent: 2},
+	"SDG": {Code: "SDG", Numeric: "938", Name: "Sudanese Pound", Exponent: 2},
+	"SEK": {Code: "SEK", Numeric: "752", Name: "Swedish Krona", Exponent: 2},
+	"SGD": {Code: "SGD", Numeric: "702", Name: "Singapore Dollar", Exponent: 2},
+	"SHP": {Code: "SHP", Numeric: "654", Name: "Saint Helena Pound", Exponent: 2},
+	"SLE": {Code: "SLE", Numeric: "925", Name: "Leone", Exponent: 2},
+	"SLL": {Code: "SLL", Numeric: "694", Name: "Leone", Exponent: 2},
+	"SOS": {Code: "SOS", Numeric: "706", Name: "Somali Shilling", Exponent: 2},
+	"SRD": {Code: "SRD", Numeric: "968", Name: "Surinam Dollar", Exponent: 2},
+	"SSP": {Code: "SSP", Numeric: "728", Name: "South Sudanese Pound", Exponent: 2},
+	"STN": {Code: "STN", Numeric: "930", Name: "Dobra", Exponent: 2},
+	"SVC": {Code: "SVC", Numeric: "222", Name: "El Salvador Colon", Exponent: 2},
+	"SYP": {Code: "SYP", Numeric: "760", Name: "Syrian Pound", Exponent: 2},
+	"SZL": {Code: "SZL", Numeric: "748", Name: "Lilangeni", Exponent: 2},
+	"THB": {Code: "THB", Numeric: "764", Name: "Baht", Exponent: 2, Symbol: "฿"},
+	"TJS": {Code: "TJS", Numeric: "972", Name: "Somoni", Exponent: 2},
+	"TMT": {Code: "TMT", Numeric: "934", Name: "Turkmenistan New Manat", Exponent: 2},
+	"TND": {Code: "TND", Numeric: "788", Name: "Tunisian Dinar", Exponent: 3},
+	"TOP": {Code: "TOP", Numeric: "776", Name: "Pa’anga", Exponent: 2},
+	"TRY": {Code: "TRY", Numeric: "949", Name: "Turkish Lira", Exponent: 2, Symbol: "₺"},
+	"TTD": {Code: "TTD", Numeric: "780", Name: "Trinidad and Tobago Dollar", Exponent: 2},
+	"TWD": {Code: "TWD", Numeric: "901", Name: "New Taiwan Dollar", Exponent: 2},
+	"TZS": {Code: "TZS", Numeric: "834", Name: "Tanzanian Shilling", Exponent: 2},
+	"UAH": {Code: "UAH", Numeric: "980", Name: "Hryvnia", Exponent: 2, Symbol: "₴"},
+	"UGX": {Code: "UGX", Numeric: "800", Name: "Uganda Shilling", Exponent: 0},
+	"USD": {Code: "USD", Numeric: "840", Name: "US Dollar", Exponent: 2, Symbol: "$"},
+	"USN": {Code: "USN", Numeric: "997", Name: "US Dollar (Next day)", Exponent: 2},
+	"UYI": {Code: "UYI", Numeric: "940", Name: "Uruguay Peso en Unidades Indexadas (UI)", Exponent: 0},
+	"UYU": {Code: "UYU", Numeric: "858", Name: "Peso Uruguayo", Exponent: 2},
+	"UYW": {Code: "UYW", Numeric: "927", Name: "Unidad Previsional", Exponent: 4},
+	"UZS": {Code: "UZS", Numeric: "860", Name: "Uzbekistan Sum", Exponent: 2},
+	"VED": {Code: "VED", Numeric: "926", Name: "Bolívar Soberano", Exponent: 2},
+	"VES": {Code: "VES", Numeric: "928", Name: "Bolívar Soberano", Exponent: 2},
+	"VND": {Code: "VND", Numeric: "704", Name: "Dong", Exponent: 0, Symbol: "₫", SymbolAfter: true},
+	"VUV": {Code: "VUV", Numeric: "548", Name: "Vatu", Exponent: 0},
+	"WST": {Code: "WST", Numeric: "882", Name: "Tala", Exponent: 2},
+	"XAF": {Code: "XAF", Numeric: "950", Name: "CFA Franc BEAC", Exponent: 0},
+	"XCD": {Code: "XCD", Numeric: "951", Name: "East Caribbean Dollar", Exponent: 2},
+	"XOF": {Code: "XOF", Numeric: "952", Name: "CFA Franc BCEAO", Exponent: 0},
+	"XPF": {Code: "XPF", Numeric: "953", Name: "CFP Franc", Exponent: 0},
+	"YER": {Code: "YER", Numeric: "886", Name: "Yemeni Rial", Exponent: 2},
+	"ZAR": {Code: "ZAR", Numeric: "710", Name: "Rand", Exponent: 2},
+	"ZMW": {Code: "ZMW", Numeric: "967", Name: "Zambian Kwacha", Exponent: 2},
+	"ZWL": {Code: "ZWL", Numeric: "932", Name: "Zimbabwe Dollar", Exponent: 2},
+}
@@ -0,0 +1,177 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+// Report lists the inconsistencies found in the ledger. An empty report means every
+// balance is the sum of its entries and every transfer is booked by exactly two entries.
+type Report struct {
+	CheckedAt           time.Time            `json:"checked_at"`
+	AccountsChecked     int64                `json:"accounts_checked"`
+	EntriesChecked      int64                `json:"entries_checked"`
+	TransfersChecked    int64                `json:"transfers_checked"`
+	BalanceDrifts       []BalanceDrift       `json:"balance_drifts"`
+	OrphanedEntries     []OrphanedEntry      `json:"orphaned_entries"`
+	UnbalancedTransfers []UnbalancedTransfer `json:"unbalanced_transfers"`
+}
+
+// BalanceDrift is an account whose balance differs from the sum of its entries by Drift
+type BalanceDrift struct {
+	AccountID    int64        `json:"account_id"`
+	Owner        string       `json:"owner"`
+	Balance      money.Amount `json:"balance"`
+	EntriesTotal money.Amount `json:"entries_total"`
+	Drift        money.Amount `json:"drift"`
+}
+
+// OrphanedEntry is an entry written neither by a transfer nor by a balance adjustment
+type OrphanedEntry struct {
+	EntryID   int64        `json:"entry_id"`
+	AccountID int64        `json:"account_id"`
+	Amount    money.Amount `json:"amount"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// UnbalancedTransfer is a transfer that is not booked by exactly one debit of Amount on the
+// from account and one credit of ToAmount on the to account
+type UnbalancedTransfer struct {
+	TransferID    int64        `json:"transfer_id"`
+	FromAccountID int64        `json:"from_account_id"`
+	ToAccountID   int64        `json:"to_account_id"`
+	Amount        money.Amount `json:"amount"`
+	ToAmount      money.Amount `json:"to_amount"`
+	EntryCount    int64        `json:"entry_count"`
+	Problem       string       `json:"problem"`
+}
+
+// Run scans the ledger of the store
+func Run(ctx context.Context, store db.Store) (Report, error) {
+	result, err := store.ReconcileTx(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("cannot scan ledger: %w", err)
+	}
+	return NewReport(result, time.Now()), nil
+}
+
+func NewReport(result db.ReconcileTxResult, checkedAt time.Time) Report {
+	report := Report{
+		CheckedAt:           checkedAt,
+		AccountsChecked:     result.Counts.Accounts,
+		EntriesChecked:      result.Counts.Entries,
+		TransfersChecked:    result.Counts.Transfers,
+		BalanceDrifts:       make([]BalanceDrift, 0, len(result.BalanceDrifts)),
+		OrphanedEntries:     make([]OrphanedEntry, 0, len(result.OrphanedEntries)),
+		UnbalancedTransfers: make([]UnbalancedTransfer, 0, len(result.UnbalancedTransfers)),
+	}
+
+	for _, row := range result.BalanceDrifts {
+		report.BalanceDrifts = append(report.BalanceDrifts, BalanceDrift{
+			AccountID:    row.ID,
+			Owner:        row.Owner,
+			Balance:      money.New(row.Balance, row.Currency),
+			EntriesTotal: money.New(row.EntriesTotal, row.Currency),
+			Drift:        money.New(row.Balance-row.EntriesTotal, row.Currency),
+		})
+	}
+
+	for _, row := range result.OrphanedEntries {
+		report.OrphanedEntries = append(report.OrphanedEntries, OrphanedEntry{
+			EntryID:   row.ID,
+			AccountID: row.AccountID,
+			Amount:    money.New(row.Amount, row.Currency),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	for _, row := range result.UnbalancedTransfers {
+		report.UnbalancedTransfers = append(report.UnbalancedTransfers, UnbalancedTransfer{
+			TransferID:    row.Transfer.ID,
+			FromAccountID: row.Transfer.FromAccountID,
+			ToAccountID:   row.Transfer.ToAccountID,
+			Amount:        money.New(row.Transfer.Amount, row.FromCurrency),
+			ToAmount:      money.New(row.Transfer.ToAmount, row.ToCurrency),
+			EntryCount:    row.EntryCount,
+			Problem:       transferProblem(row),
+		})
+	}
+
+	return report
+}
+
+func transferProblem(row db.ListUnbalancedTransfersRow) string {
+	switch {
+	case row.EntryCount == 0:
+		return "no entries"
+	case row.DebitCount != 1:
+		return fmt.Sprintf("%d matching debits on account %d", row.DebitCount, row.Transfer.FromAccountID)
+	case row.CreditCount != 1:
+		return fmt.Sprintf("%d matching credits on account %d", row.CreditCount, row.Transfer.ToAccountID)
+	default:
+		return fmt.Sprintf("%d entries instead of 2", row.EntryCount)
+	}
+}
+
+// Issues is the number of inconsistencies in the report
+func (report Report) Issues() int {
+	return len(report.BalanceDrifts) + len(report.OrphanedEntries) + len(report.UnbalancedTransfers)
+}
+
+// WriteSummary writes the report for a human reader
+func (report Report) WriteSummary(w io.Writer) error {
+	p := &printer{w: w}
+
+	p.printf("Ledger reconciliation at %s\n", report.CheckedAt.UTC().Format(time.RFC3339))
+	p.printf("Checked %d accounts, %d entries and %d transfers\n",
+		report.AccountsChecked, report.EntriesChecked, report.TransfersChecked)
+
+	if report.Issues() == 0 {
+		p.printf("OK: no issues found\n")
+		return p.err
+	}
+	p.printf("FAILED: %d issues found\n", report.Issues())
+
+	if len(report.BalanceDrifts) > 0 {
+		p.printf("\nDrifted balances (%d):\n", len(report.BalanceDrifts))
+		for _, drift := range report.BalanceDrifts {
+			p.printf("  account %d (%s): balance %s, entries total %s, drift %s\n",
+				drift.AccountID, drift.Owner, drift.Balance.Display(), drift.EntriesTotal.Display(), drift.Drift.Display())
+		}
+	}
+
+	if len(report.OrphanedEntries) > 0 {
+		p.printf("\nOrphaned entries (%d):\n", len(report.OrphanedEntries))
+		for _, entry := range report.OrphanedEntries {
+			p.printf("  entry %d on account %d: %s at %s\n",
+				entry.EntryID, entry.AccountID, entry.Amount.Display(), entry.CreatedAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if len(report.UnbalancedTransfers) > 0 {
+		p.printf("\nUnbalanced transfers (%d):\n", len(report.UnbalancedTransfers))
+		for _, transfer := range report.UnbalancedTransfers {
+			p.printf("  transfer %d from account %d to account %d (%s): %s\n",
+				transfer.TransferID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount.Display(), transfer.Problem)
+		}
+	}
+
+	return p.err
+}
+
+// printer keeps the first write error so WriteSummary does not check every line
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
@@ -0,0 +1,69 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReport(t *testing.T) {
+	checkedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := db.ReconcileTxResult{
+		Counts: db.CountLedgerRow{Accounts: 3, Entries: 10, Transfers: 4},
+		BalanceDrifts: []db.ListBalanceDriftsRow{
+			{ID: 1, Owner: "alice", Currency: money.USD, Balance: 10000, EntriesTotal: 9950},
+		},
+		OrphanedEntries: []db.ListOrphanedEntriesRow{
+			{ID: 5, AccountID: 1, Amount: -50, Currency: money.USD, CreatedAt: checkedAt},
+		},
+		UnbalancedTransfers: []db.ListUnbalancedTransfersRow{
+			{Transfer: db.Transfer{ID: 1, FromAccountID: 1, ToAccountID: 2, Amount: 100, ToAmount: 100}, FromCurrency: money.USD, ToCurrency: money.USD},
+			{Transfer: db.Transfer{ID: 2, FromAccountID: 1, ToAccountID: 2, Amount: 100, ToAmount: 100}, FromCurrency: money.USD, ToCurrency: money.USD, EntryCount: 1, CreditCount: 1},
+			{Transfer: db.Transfer{ID: 3, FromAccountID: 1, ToAccountID: 2, Amount: 100, ToAmount: 100}, FromCurrency: money.USD, ToCurrency: money.USD, EntryCount: 2, DebitCount: 1},
+			{Transfer: db.Transfer{ID: 4, FromAccountID: 1, ToAccountID: 2, Amount: 100, ToAmount: 100}, FromCurrency: money.USD, ToCurrency: money.USD, EntryCount: 3, DebitCount: 1, CreditCount: 1},
+		},
+	}
+
+	report := NewReport(result, checkedAt)
+	require.Equal(t, 6, report.Issues())
+	require.Equal(t, int64(10), report.EntriesChecked)
+	require.Equal(t, "0.50", report.BalanceDrifts[0].Drift.String())
+	require.Equal(t, "-0.50", report.OrphanedEntries[0].Amount.String())
+	require.Equal(t, "no entries", report.UnbalancedTransfers[0].Problem)
+	require.Equal(t, "0 matching debits on account 1", report.UnbalancedTransfers[1].Problem)
+	require.Equal(t, "0 matching credits on account 2", report.UnbalancedTransfers[2].Problem)
+	require.Equal(t, "3 entries instead of 2", report.UnbalancedTransfers[3].Problem)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, "99.50", decoded["balance_drifts"].([]any)[0].(map[string]any)["entries_total"])
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSummary(&buf))
+	summary := buf.String()
+	require.Contains(t, summary, "FAILED: 6 issues found")
+	require.Contains(t, summary, "account 1 (alice): balance $100.00, entries total $99.50, drift $0.50")
+	require.Contains(t, summary, "entry 5 on account 1: -$0.50")
+	require.Contains(t, summary, "transfer 1 from account 1 to account 2 ($1.00): no entries")
+}
+
+func TestEmptyReport(t *testing.T) {
+	report := NewReport(db.ReconcileTxResult{Counts: db.CountLedgerRow{Accounts: 2}}, time.Now())
+	require.Zero(t, report.Issues())
+
+	// empty lists are encoded as [] so clients do not have to handle null
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"balance_drifts":[]`)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSummary(&buf))
+	require.Contains(t, buf.String(), "OK: no issues found")
+}
@@ -0,0 +1,64 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVRenderer writes one row per entry between an opening and a closing balance row
+type CSVRenderer struct{}
+
+func (CSVRenderer) Format() string {
+	return "csv"
+}
+
+func (CSVRenderer) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (CSVRenderer) Render(w io.Writer, statement Statement) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"date", "entry_id", "transfer_id", "description", "amount", "balance", "currency"},
+		{formatTime(statement.From), "", "", "Opening balance", "", statement.OpeningBalance.String(), statement.Currency},
+	}
+	for _, line := range statement.Lines {
+		transferID := ""
+		if line.TransferID != 0 {
+			transferID = strconv.FormatInt(line.TransferID, 10)
+		}
+		rows = append(rows, []string{
+			formatTime(line.Date),
+			strconv.FormatInt(line.EntryID, 10),
+			transferID,
+			csvText(line.Description),
+			line.Amount.String(),
+			line.Balance.String(),
+			statement.Currency,
+		})
+	}
+	rows = append(rows, []string{formatTime(statement.To), "", "", "Closing balance", "", statement.ClosingBalance.String(), statement.Currency})
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// csvText keeps spreadsheets from running a text cell as a formula, which descriptions that
+// carry user input such as adjustment reasons could otherwise smuggle in. Amounts are left
+// alone so that negative ones stay numbers.
+func csvText(s string) string {
+	if len(s) > 0 && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,157 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+	`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+// ofxBankID identifies the bank in BANKACCTFROM, there is no routing number to use
+const ofxBankID = "SIMPLEBANK"
+
+// OFXRenderer writes an OFX 2.2 bank statement response, as imported by accounting software
+type OFXRenderer struct{}
+
+func (OFXRenderer) Format() string {
+	return "ofx"
+}
+
+func (OFXRenderer) ContentType() string {
+	return "application/x-ofx"
+}
+
+type ofxDocument struct {
+	XMLName xml.Name           `xml:"OFX"`
+	SignOn  ofxSignOn          `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    ofxBankTransaction `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	ServerAt string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxBankTransaction struct {
+	UID       string       `xml:"TRNUID"`
+	Status    ofxStatus    `xml:"STATUS"`
+	Statement ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	Currency     string             `xml:"CURDEF"`
+	Account      ofxAccount         `xml:"BANKACCTFROM"`
+	Transactions ofxTransactionList `xml:"BANKTRANLIST"`
+	Ledger       ofxBalance         `xml:"LEDGERBAL"`
+}
+
+type ofxAccount struct {
+	BankID    string `xml:"BANKID"`
+	AccountID string `xml:"ACCTID"`
+	Type      string `xml:"ACCTTYPE"`
+}
+
+type ofxTransactionList struct {
+	Start        string           `xml:"DTSTART"`
+	End          string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	ID     string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+func (OFXRenderer) Render(w io.Writer, statement Statement) error {
+	transactions := make([]ofxTransaction, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		kind := "CREDIT"
+		if line.Amount.IsNegative() {
+			kind = "DEBIT"
+		}
+		transaction := ofxTransaction{
+			Type:   kind,
+			Posted: ofxTime(line.Date),
+			Amount: line.Amount.String(),
+			ID:     strconv.FormatInt(line.EntryID, 10),
+			Name:   ofxName(line.Description),
+		}
+		if len(line.Description) > len(transaction.Name) {
+			transaction.Memo = line.Description
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	document := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:   ofxStatus{Code: 0, Severity: "INFO"},
+			ServerAt: ofxTime(statement.GeneratedAt),
+			Language: "ENG",
+		},
+		Bank: ofxBankTransaction{
+			UID:    fmt.Sprintf("%d-%d", statement.AccountID, statement.To.Unix()),
+			Status: ofxStatus{Code: 0, Severity: "INFO"},
+			Statement: ofxStatement{
+				Currency: statement.Currency,
+				Account: ofxAccount{
+					BankID:    ofxBankID,
+					AccountID: strconv.FormatInt(statement.AccountID, 10),
+					Type:      "CHECKING",
+				},
+				Transactions: ofxTransactionList{
+					Start:        ofxTime(statement.From),
+					End:          ofxTime(statement.To),
+					Transactions: transactions,
+				},
+				Ledger: ofxBalance{
+					Amount: statement.ClosingBalance.String(),
+					AsOf:   ofxTime(statement.To),
+				},
+			},
+		},
+	}
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ofxTime formats t as an OFX datetime in UTC
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// ofxName truncates a description to the 32 characters allowed in NAME
+func ofxName(description string) string {
+	runes := []rune(description)
+	if len(runes) > 32 {
+		return string(runes[:32])
+	}
+	return description
+}
@@ -0,0 +1,128 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pdfPageWidth    = 595 // A4 in points
+	pdfPageHeight   = 842
+	pdfMargin       = 50
+	pdfFontSize     = 8
+	pdfLineHeight   = 11
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+// PDFRenderer writes a plain text statement as a PDF document. It only uses the standard
+// Courier font so the columns line up without embedding any font.
+type PDFRenderer struct{}
+
+func (PDFRenderer) Format() string {
+	return "pdf"
+}
+
+func (PDFRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+func (PDFRenderer) Render(w io.Writer, statement Statement) error {
+	return writePDF(w, paginate(statementText(statement), pdfLinesPerPage))
+}
+
+func statementText(statement Statement) []string {
+	row := func(date string, description string, amount string, balance string) string {
+		return fmt.Sprintf("%-20s  %-40.40s  %16s  %16s", date, description, amount, balance)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Statement of account %d (%s)", statement.AccountID, statement.Currency),
+		"Owner: " + statement.Owner,
+		fmt.Sprintf("Period: %s to %s", formatTime(statement.From), formatTime(statement.To)),
+		"",
+		row("Date", "Description", "Amount", "Balance"),
+		row(formatTime(statement.From), "Opening balance", "", statement.OpeningBalance.String()),
+	}
+	for _, line := range statement.Lines {
+		lines = append(lines, row(formatTime(line.Date), line.Description, line.Amount.String(), line.Balance.String()))
+	}
+	lines = append(lines,
+		row(formatTime(statement.To), "Closing balance", "", statement.ClosingBalance.String()),
+		"",
+		"Generated at "+formatTime(statement.GeneratedAt),
+	)
+	return lines
+}
+
+func paginate(lines []string, size int) [][]string {
+	pages := make([][]string, 0, len(lines)/size+1)
+	for len(lines) > size {
+		pages = append(pages, lines[:size])
+		lines = lines[size:]
+	}
+	return append(pages, lines)
+}
+
+// writePDF writes a PDF 1.4 file with one page per group of lines. Objects are numbered
+// as catalog (1), page tree (2), font (3), then a page and its content stream per page.
+func writePDF(w io.Writer, pages [][]string) error {
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+		}
+		content.WriteString("ET")
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// pdfEscape escapes a line for a PDF literal string. Characters outside printable ASCII
+// are replaced since the standard fonts cannot show them without an embedded encoding.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported statement format")
+
+// Renderer writes a statement in one file format. New formats are added by implementing
+// Renderer and passing it to NewRegistry.
+type Renderer interface {
+	// Format is the value of the format query parameter, it is also used as file extension
+	Format() string
+	ContentType() string
+	Render(w io.Writer, statement Statement) error
+}
+
+// Registry looks renderers up by format
+type Registry struct {
+	renderers map[string]Renderer
+}
+
+func NewRegistry(renderers ...Renderer) *Registry {
+	registry := &Registry{renderers: make(map[string]Renderer, len(renderers))}
+	for _, renderer := range renderers {
+		registry.renderers[renderer.Format()] = renderer
+	}
+	return registry
+}
+
+// DefaultRegistry supports CSV, OFX and PDF statements
+func DefaultRegistry() *Registry {
+	return NewRegistry(CSVRenderer{}, OFXRenderer{}, PDFRenderer{})
+}
+
+func (registry *Registry) Lookup(format string) (Renderer, error) {
+	renderer, ok := registry.renderers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, supported formats are %s",
+			ErrUnsupportedFormat, format, strings.Join(registry.Formats(), ", "))
+	}
+	return renderer, nil
+}
+
+// Formats returns the supported formats in alphabetical order
+func (registry *Registry) Formats() []string {
+	formats := make([]string, 0, len(registry.renderers))
+	for format := range registry.renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
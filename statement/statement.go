@@ -0,0 +1,89 @@
+package statement
+
+import (
+	"fmt"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+)
+
+// Statement lists the entries of an account over [From, To) with the balance after each of them
+type Statement struct {
+	AccountID      int64
+	Owner          string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance money.Amount
+	ClosingBalance money.Amount
+	Lines          []Line
+	GeneratedAt    time.Time
+}
+
+// Line is one entry of the statement
+type Line struct {
+	EntryID     int64
+	TransferID  int64
+	Date        time.Time
+	Description string
+	Amount      money.Amount
+	Balance     money.Amount
+}
+
+// Build turns the rows read by StatementTx into a statement. It fails if the running
+// balance does not end on the closing balance, which would mean the ledger is inconsistent.
+func Build(result db.StatementTxResult, from time.Time, to time.Time) (Statement, error) {
+	account := result.Account
+	statement := Statement{
+		AccountID:      account.ID,
+		Owner:          account.Owner,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: money.New(result.OpeningBalance, account.Currency),
+		ClosingBalance: money.New(result.ClosingBalance, account.Currency),
+		Lines:          make([]Line, 0, len(result.Entries)),
+		GeneratedAt:    time.Now(),
+	}
+
+	balance := statement.OpeningBalance
+	for _, row := range result.Entries {
+		amount := money.New(row.Entry.Amount, account.Currency)
+
+		var err error
+		balance, err = balance.Add(amount)
+		if err != nil {
+			return Statement{}, fmt.Errorf("entry [%d]: %w", row.Entry.ID, err)
+		}
+
+		statement.Lines = append(statement.Lines, Line{
+			EntryID:     row.Entry.ID,
+			TransferID:  row.Entry.TransferID.Int64,
+			Date:        row.Entry.CreatedAt,
+			Description: describe(account.ID, row),
+			Amount:      amount,
+			Balance:     balance,
+		})
+	}
+
+	if balance.Units() != statement.ClosingBalance.Units() {
+		return Statement{}, fmt.Errorf("statement of account [%d] does not reconcile: running balance %s, closing balance %s",
+			account.ID, balance, statement.ClosingBalance)
+	}
+
+	return statement, nil
+}
+
+func describe(accountID int64, row db.ListStatementEntriesRow) string {
+	switch {
+	case row.Entry.TransferID.Valid && row.TransferFromAccountID.Int64 == accountID:
+		return fmt.Sprintf("Transfer #%d to account %d", row.Entry.TransferID.Int64, row.TransferToAccountID.Int64)
+	case row.Entry.TransferID.Valid:
+		return fmt.Sprintf("Transfer #%d from account %d", row.Entry.TransferID.Int64, row.TransferFromAccountID.Int64)
+	case row.AdjustmentReason.Valid:
+		return "Balance adjustment: " + row.AdjustmentReason.String
+	default:
+		return fmt.Sprintf("Entry #%d", row.Entry.ID)
+	}
+}
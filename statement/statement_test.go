@@ -0,0 +1,179 @@
+package statement
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/lamdangtung/golang-sample-bank/db/sqlc"
+	"github.com/lamdangtung/golang-sample-bank/money"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatementResult(lines int) db.StatementTxResult {
+	account := db.Account{ID: 1, Owner: "alice", Currency: money.USD, Balance: 10000}
+	result := db.StatementTxResult{
+		Account:        account,
+		OpeningBalance: 5000,
+		ClosingBalance: 5000,
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= lines; i++ {
+		amount := int64(i * 100)
+		if i%2 == 0 {
+			amount = -amount
+		}
+		result.ClosingBalance += amount
+		result.Entries = append(result.Entries, db.ListStatementEntriesRow{
+			Entry: db.Entry{ID: int64(i), AccountID: account.ID, Amount: amount, CreatedAt: from.Add(time.Duration(i) * time.Hour)},
+		})
+	}
+	return result
+}
+
+func TestBuild(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	result := newStatementResult(0)
+	result.ClosingBalance = 5300
+	result.Entries = []db.ListStatementEntriesRow{
+		{
+			Entry:                 db.Entry{ID: 1, AccountID: 1, Amount: -200, TransferID: sql.NullInt64{Int64: 10, Valid: true}},
+			TransferFromAccountID: sql.NullInt64{Int64: 1, Valid: true},
+			TransferToAccountID:   sql.NullInt64{Int64: 2, Valid: true},
+		},
+		{
+			Entry:                 db.Entry{ID: 2, AccountID: 1, Amount: 400, TransferID: sql.NullInt64{Int64: 11, Valid: true}},
+			TransferFromAccountID: sql.NullInt64{Int64: 3, Valid: true},
+			TransferToAccountID:   sql.NullInt64{Int64: 1, Valid: true},
+		},
+		{
+			Entry:            db.Entry{ID: 3, AccountID: 1, Amount: 100},
+			AdjustmentReason: sql.NullString{String: "refund", Valid: true},
+		},
+		{
+			Entry: db.Entry{ID: 4, AccountID: 1, Amount: 0},
+		},
+	}
+
+	statement, err := Build(result, from, to)
+	require.NoError(t, err)
+	require.Equal(t, "50.00", statement.OpeningBalance.String())
+	require.Equal(t, "53.00", statement.ClosingBalance.String())
+	require.Len(t, statement.Lines, 4)
+
+	require.Equal(t, "Transfer #10 to account 2", statement.Lines[0].Description)
+	require.Equal(t, int64(10), statement.Lines[0].TransferID)
+	require.Equal(t, "48.00", statement.Lines[0].Balance.String())
+	require.Equal(t, "Transfer #11 from account 3", statement.Lines[1].Description)
+	require.Equal(t, "52.00", statement.Lines[1].Balance.String())
+	require.Equal(t, "Balance adjustment: refund", statement.Lines[2].Description)
+	require.Equal(t, "Entry #4", statement.Lines[3].Description)
+	require.Equal(t, "53.00", statement.Lines[3].Balance.String())
+
+	result.ClosingBalance = 5400
+	_, err = Build(result, from, to)
+	require.ErrorContains(t, err, "does not reconcile")
+}
+
+func TestRegistry(t *testing.T) {
+	registry := DefaultRegistry()
+	require.Equal(t, []string{"csv", "ofx", "pdf"}, registry.Formats())
+
+	renderer, err := registry.Lookup("OFX")
+	require.NoError(t, err)
+	require.Equal(t, "ofx", renderer.Format())
+
+	_, err = registry.Lookup("xlsx")
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestRenderCSV(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	statement, err := Build(newStatementResult(2), from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+
+	descriptions := []string{"=HYPERLINK(\"http://example.com\")", "-1+2"}
+	for i := range statement.Lines {
+		statement.Lines[i].Description = descriptions[i]
+	}
+	statement.Lines = append(statement.Lines,
+		Line{EntryID: 3, Description: "+1", Amount: money.New(0, money.USD), Balance: statement.ClosingBalance},
+		Line{EntryID: 4, Description: "@SUM(A1:A2)", Amount: money.New(0, money.USD), Balance: statement.ClosingBalance},
+		Line{EntryID: 5, Description: "Entry #5", Amount: money.New(0, money.USD), Balance: statement.ClosingBalance},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, CSVRenderer{}.Render(&buf, statement))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 8)
+
+	// text cells that spreadsheets would run as formulas are quoted, negative amounts are kept
+	require.Equal(t, `'=HYPERLINK("http://example.com")`, rows[2][3])
+	require.Equal(t, "'-1+2", rows[3][3])
+	require.Equal(t, "-2.00", rows[3][4])
+	require.Equal(t, "'+1", rows[4][3])
+	require.Equal(t, "'@SUM(A1:A2)", rows[5][3])
+	require.Equal(t, "Entry #5", rows[6][3])
+	require.Equal(t, "Closing balance", rows[7][3])
+}
+
+func TestRenderOFX(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	statement, err := Build(newStatementResult(3), from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, OFXRenderer{}.Render(&buf, statement))
+
+	var document ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &document))
+	transactions := document.Bank.Statement.Transactions.Transactions
+	require.Len(t, transactions, 3)
+	require.Equal(t, "CREDIT", transactions[0].Type)
+	require.Equal(t, "DEBIT", transactions[1].Type)
+	require.Equal(t, "-2.00", transactions[1].Amount)
+	require.Equal(t, "20240101010000[0:GMT]", transactions[0].Posted)
+	require.Equal(t, "52.00", document.Bank.Statement.Ledger.Amount)
+}
+
+func TestRenderPDF(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	statement, err := Build(newStatementResult(150), from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	statement.Owner = "alice (joint)"
+
+	var buf bytes.Buffer
+	require.NoError(t, PDFRenderer{}.Render(&buf, statement))
+	pdf := buf.String()
+
+	require.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	require.Contains(t, pdf, `Owner: alice \(joint\)`)
+	require.Contains(t, pdf, "/Count 3")
+
+	// every xref entry must point at the start of its object
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	require.NotNil(t, match)
+	xref, err := strconv.Atoi(match[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	require.Len(t, offsets, 3+2*3)
+	for i, offset := range offsets {
+		n, err := strconv.Atoi(offset[1])
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(pdf[n:], fmt.Sprintf("%d 0 obj\n", i+1)))
+	}
+}
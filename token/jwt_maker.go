@@ -24,12 +24,18 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return jwtMaker, nil
 }
 
-// CreateToken create token for specified username and duration
-func (maker JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken create token for specified username, role and duration
+func (maker JWTMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.CreateScopedToken(username, role, "", duration)
+}
+
+// CreateScopedToken create token for specified username, role, scope and duration
+func (maker JWTMaker) CreateScopedToken(username string, role string, scope string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
 	if err != nil {
 		return "", nil, err
 	}
+	payload.Scope = scope
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	token, err := jwtToken.SignedString([]byte(maker.secretKey))
@@ -14,12 +14,13 @@ func TestJWTMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomUsername()
+	role := "banker"
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotNil(t, payload)
@@ -31,6 +32,7 @@ func TestJWTMaker(t *testing.T) {
 	require.WithinDuration(t, payload.ExpiredAt, expiredAt, time.Minute)
 	require.WithinDuration(t, payload.IssuedAt, issuedAt, time.Minute)
 	require.Equal(t, payload.Username, username)
+	require.Equal(t, payload.Role, role)
 
 }
 
@@ -40,7 +42,7 @@ func TestExpiredJWTToken(t *testing.T) {
 
 	username := util.RandomUsername()
 	duration := time.Minute
-	token, payload, err := maker.CreateToken(username, -duration)
+	token, payload, err := maker.CreateToken(username, "depositor", -duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotNil(t, payload)
@@ -52,7 +54,7 @@ func TestExpiredJWTToken(t *testing.T) {
 }
 
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
-	payload, err := NewPayload(util.RandomUsername(), time.Minute)
+	payload, err := NewPayload(util.RandomUsername(), "depositor", time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
@@ -67,3 +69,24 @@ func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestJWTScopedToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateScopedToken(util.RandomUsername(), "depositor", ScopeMFAPending, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, ScopeMFAPending, payload.Scope)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NoError(t, payload.CheckScope(ScopeMFAPending))
+	require.ErrorIs(t, payload.CheckScope(""), ErrInvalidToken)
+
+	token, _, err = maker.CreateToken(util.RandomUsername(), "depositor", time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NoError(t, payload.CheckScope(""))
+	require.ErrorIs(t, payload.CheckScope(ScopeMFAPending), ErrInvalidToken)
+}
@@ -3,8 +3,10 @@ package token
 import "time"
 
 type Maker interface {
-	/// CreateToken create token for specified username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	/// CreateToken create token for specified username, role and duration
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
+	/// CreateScopedToken creates a token that can only be used for the given scope
+	CreateScopedToken(username string, role string, scope string, duration time.Duration) (string, *Payload, error)
 	/// VerifyToken checks if the token is valid or invalid
 	VerifyToken(token string) (*Payload, error)
 }
@@ -28,12 +28,18 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken create token for specified username and duration
-func (maker PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken create token for specified username, role and duration
+func (maker PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.CreateScopedToken(username, role, "", duration)
+}
+
+// CreateScopedToken create token for specified username, role, scope and duration
+func (maker PasetoMaker) CreateScopedToken(username string, role string, scope string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
 	if err != nil {
 		return "", nil, err
 	}
+	payload.Scope = scope
 	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
 	return token, payload, err
 }
@@ -13,12 +13,13 @@ func TestPasetoMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomUsername()
+	role := "banker"
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotNil(t, payload)
@@ -30,6 +31,7 @@ func TestPasetoMaker(t *testing.T) {
 	require.WithinDuration(t, payload.ExpiredAt, expiredAt, time.Minute)
 	require.WithinDuration(t, payload.IssuedAt, issuedAt, time.Minute)
 	require.Equal(t, payload.Username, username)
+	require.Equal(t, payload.Role, role)
 
 }
 
@@ -39,7 +41,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 
 	username := util.RandomUsername()
 	duration := time.Minute
-	token, payload, err := maker.CreateToken(username, -duration)
+	token, payload, err := maker.CreateToken(username, "depositor", -duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotNil(t, payload)
@@ -49,3 +51,16 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoScopedToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateScopedToken(util.RandomUsername(), "depositor", ScopeMFAPending, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, ScopeMFAPending, payload.Scope)
+	require.ErrorIs(t, payload.CheckScope(""), ErrInvalidToken)
+}
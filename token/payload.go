@@ -10,17 +10,30 @@ import (
 var (
 	ErrExpiredToken = errors.New("token has expired")
 	ErrInvalidToken = errors.New("token is invalid")
+	// ErrRevokedToken is returned for a token issued before its user last changed their password
+	ErrRevokedToken = errors.New("token was issued before the last password change")
 )
 
+// ScopeMFAPending is the scope of the token given after the password step of a login when the
+// user has two-factor authentication, it can only be exchanged for access and refresh tokens
+const ScopeMFAPending = "mfa_pending"
+
+// ScopeRefresh is the scope of refresh tokens, they can only be exchanged for new tokens
+// and are not accepted as access tokens
+const ScopeRefresh = "refresh"
+
 // Payload contains the payload data of the token
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	// Scope is empty for access tokens
+	Scope     string    `json:"scope,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -29,6 +42,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  now,
 		ExpiredAt: now.Add(duration),
 	}
@@ -41,3 +55,20 @@ func (payload *Payload) Valid() error {
 	}
 	return nil
 }
+
+// CheckScope fails with ErrInvalidToken unless the token was created for the scope
+func (payload *Payload) CheckScope(scope string) error {
+	if payload.Scope != scope {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// CheckPasswordChangedAt fails with ErrRevokedToken when the token is older than the last
+// password change of its user
+func (payload *Payload) CheckPasswordChangedAt(passwordChangedAt time.Time) error {
+	if payload.IssuedAt.Before(passwordChangedAt) {
+		return ErrRevokedToken
+	}
+	return nil
+}
@@ -10,9 +10,40 @@ type Config struct {
 	DBDriver             string        `mapstructure:"DB_DRIVER"`
 	DBSource             string        `mapstructure:"DB_SOURCE"`
 	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
+	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	HTTPGatewayAddress   string        `mapstructure:"HTTP_GATEWAY_ADDRESS"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	IdempotencyKeyTTL    time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	// IdempotencyKeyPurgeInterval is how often expired idempotency keys are deleted, 0 never deletes them
+	IdempotencyKeyPurgeInterval time.Duration `mapstructure:"IDEMPOTENCY_KEY_PURGE_INTERVAL"`
+	// PublicBaseURL is where users reach the HTTP API, it prefixes the links sent by email
+	PublicBaseURL         string        `mapstructure:"PUBLIC_BASE_URL"`
+	VerifyEmailDuration   time.Duration `mapstructure:"VERIFY_EMAIL_DURATION"`
+	PasswordResetDuration time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+	// Mailer is smtp, file or memory, see mail.New
+	Mailer             string `mapstructure:"MAILER"`
+	MailDir            string `mapstructure:"MAIL_DIR"`
+	SMTPHost           string `mapstructure:"SMTP_HOST"`
+	SMTPPort           int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername       string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword       string `mapstructure:"SMTP_PASSWORD"`
+	EmailSenderAddress string `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	// TOTPIssuer is the name authenticator apps show next to the two-factor codes
+	TOTPIssuer       string        `mapstructure:"TOTP_ISSUER"`
+	MFATokenDuration time.Duration `mapstructure:"MFA_TOKEN_DURATION"`
+	// MFATransferThresholds lists the amounts from which transfers need a fresh two-factor code, see mfa.ParseTransferThresholds
+	MFATransferThresholds string `mapstructure:"MFA_TRANSFER_THRESHOLDS"`
+	// LoginAttemptStore is postgres or memory, see lockout.New
+	LoginAttemptStore       string        `mapstructure:"LOGIN_ATTEMPT_STORE"`
+	LoginMaxFailures        int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginMaxFailuresPerIP   int           `mapstructure:"LOGIN_MAX_FAILURES_PER_IP"`
+	LoginLockoutDuration    time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginMaxLockoutDuration time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT_DURATION"`
+	// TrustedProxies lists the IPs and CIDRs of the proxies whose X-Forwarded-For is believed,
+	// comma separated. Client IPs come from the connection when it is empty.
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
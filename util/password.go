@@ -17,3 +17,15 @@ func HashedPassword(password string) (string, error) {
 func CheckPassword(password string, hashedPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+// unknownUserHashedPassword is the hash of a random password that nobody knows
+const unknownUserHashedPassword = "$2a$10$OQj7qZJ/rK8AlD0ALhlMsOW94pDgtmPPA0xeSjKstvenn71ZCFh4m"
+
+// CheckPasswordOfUnknownUser always fails, but takes as long as CheckPassword so that the
+// response time of a login does not tell whether the username exists
+func CheckPasswordOfUnknownUser(password string) error {
+	if err := CheckPassword(password, unknownUserHashedPassword); err != nil {
+		return err
+	}
+	return bcrypt.ErrMismatchedHashAndPassword
+}
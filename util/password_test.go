@@ -21,3 +21,13 @@ func TestHashedPass(t *testing.T) {
 	err = CheckPassword(wrongPassword, hashedPassword)
 	require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
 }
+
+func TestCheckPasswordOfUnknownUser(t *testing.T) {
+	err := CheckPasswordOfUnknownUser(RandomPassword())
+	require.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
+
+	// the dummy hash has the cost of real ones, or the check would be noticeably faster
+	cost, err := bcrypt.Cost([]byte(unknownUserHashedPassword))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.DefaultCost, cost)
+}
@@ -5,6 +5,8 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/lamdangtung/golang-sample-bank/money"
 )
 
 const alphabet = "abcdefghijklmnopqrstuvwxyz"
@@ -38,7 +40,7 @@ func RandomMoney() int64 {
 }
 
 func RandomCurrency() string {
-	currencies := []string{USD, EUR, VND}
+	currencies := []string{money.USD, money.EUR, money.VND}
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
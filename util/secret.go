@@ -0,0 +1,17 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// RandomSecret returns n bytes from crypto/rand encoded as URL-safe base64, for codes
+// that are sent to users and must not be guessable. RandomString is not suitable for this.
+func RandomSecret(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}